@@ -0,0 +1,44 @@
+package runeseg
+
+// isBidiControl returns true if the given rune is one of the bidirectional
+// formatting characters which can be used to reorder the display of text:
+// the embeddings and overrides LRE, RLE, PDF, LRO, RLO (U+202A to U+202E), the
+// isolates LRI, RLI, FSI, PDI (U+2066 to U+2069), and the implicit marks LRM
+// (U+200E), RLM (U+200F), and ALM (U+061C).
+func isBidiControl(r rune) bool {
+	return r >= 0x202a && r <= 0x202e ||
+		r >= 0x2066 && r <= 0x2069 ||
+		r == 0x200e || r == 0x200f || r == 0x061c
+}
+
+// HasBidiControls returns true if the given string contains bidirectional
+// formatting characters, i.e. embeddings and overrides (U+202A to U+202E),
+// isolates (U+2066 to U+2069), or the LRM, RLM, and ALM marks. Such characters
+// change the order in which text is displayed and may be used to make source
+// code or identifiers appear different from what they are ("Trojan Source"
+// attacks). See [BidiControlPositions] to locate them.
+func HasBidiControls(s string) bool {
+	for _, r := range s {
+		if isBidiControl(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// BidiControlPositions returns the byte offsets of all bidirectional
+// formatting characters (see [HasBidiControls]) in the given string, or nil if
+// there are none. As these characters have the grapheme cluster break property
+// Control, they always form a grapheme cluster of their own. The returned
+// positions are therefore always grapheme cluster boundaries and the
+// characters can be removed or highlighted without affecting the surrounding
+// grapheme clusters.
+func BidiControlPositions(s string) []int {
+	var positions []int
+	for pos, r := range s {
+		if isBidiControl(r) {
+			positions = append(positions, pos)
+		}
+	}
+	return positions
+}
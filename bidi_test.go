@@ -0,0 +1,46 @@
+package runeseg
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Test the detection of bidirectional formatting characters.
+func TestBidiControls(t *testing.T) {
+	for _, testCase := range []struct {
+		original  string
+		positions []int
+	}{
+		{"", nil},
+		{"access_level", nil},
+		{"\u05e9\u05dc\u05d5\u05dd", nil}, // Hebrew text itself is fine.
+		{"access_level = \"user\u202e \u2066// Check if admin\u2069 \u2066\"", []int{20, 24, 44, 48}},
+		{"\u202eecilA", []int{0}},
+		{"a\u202ab\u202cc", []int{1, 5}},
+		{"a\u200eb\u200fc\u061cd", []int{1, 5, 9}},
+		{"e\u0301\u202e\U0001F44D\u2069", []int{3, 10}},
+	} {
+		positions := BidiControlPositions(testCase.original)
+		if !reflect.DeepEqual(positions, testCase.positions) {
+			t.Errorf("BidiControlPositions(%q) is %v, expected %v", testCase.original, positions, testCase.positions)
+		}
+		if has := HasBidiControls(testCase.original); has != (len(testCase.positions) > 0) {
+			t.Errorf("HasBidiControls(%q) is %t", testCase.original, has)
+		}
+
+		// All positions must be grapheme cluster boundaries.
+		boundaries := make(map[int]bool)
+		str, state, offset := testCase.original, -1, 0
+		for len(str) > 0 {
+			var cluster string
+			boundaries[offset] = true
+			cluster, str, _, state = FirstGraphemeClusterInString(str, state)
+			offset += len(cluster)
+		}
+		for _, pos := range positions {
+			if !boundaries[pos] {
+				t.Errorf("%q: position %d is not a grapheme cluster boundary", testCase.original, pos)
+			}
+		}
+	}
+}
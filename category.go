@@ -0,0 +1,66 @@
+package runeseg
+
+import "unicode"
+
+// generalCategoryNames maps the gc* constants to the two-letter abbreviations
+// of the Unicode general categories.
+var generalCategoryNames = [...]string{
+	gcNone: "Cn",
+	gcCc:   "Cc",
+	gcZs:   "Zs",
+	gcPo:   "Po",
+	gcSc:   "Sc",
+	gcPs:   "Ps",
+	gcPe:   "Pe",
+	gcSm:   "Sm",
+	gcPd:   "Pd",
+	gcNd:   "Nd",
+	gcLu:   "Lu",
+	gcSk:   "Sk",
+	gcPc:   "Pc",
+	gcLl:   "Ll",
+	gcSo:   "So",
+	gcLo:   "Lo",
+	gcPi:   "Pi",
+	gcCf:   "Cf",
+	gcNo:   "No",
+	gcPf:   "Pf",
+	gcLC:   "LC",
+	gcLm:   "Lm",
+	gcMn:   "Mn",
+	gcMe:   "Me",
+	gcMc:   "Mc",
+	gcNl:   "Nl",
+	gcZl:   "Zl",
+	gcZp:   "Zp",
+	gcCn:   "Cn",
+	gcCs:   "Cs",
+	gcCo:   "Co",
+}
+
+// GeneralCategory returns the two-letter abbreviation of the Unicode general
+// category of the given rune, e.g. "Lu" for uppercase letters, "Pi" and "Pf"
+// for initial and final quotation marks, or "Mn" for nonspacing marks. The
+// categories are taken from the Unicode version supported by this package (see
+// [UnicodeVersion]). Unassigned code points and runes which are not valid
+// code points return "Cn".
+//
+// The package's tables group cased letters into ranges which may mix upper
+// and lower case. For these, the case is determined with the [unicode]
+// package, returning "Lu", "Ll", or "Lt".
+func GeneralCategory(r rune) string {
+	if r < 0 || r > unicode.MaxRune {
+		return "Cn"
+	}
+	_, gc := propertyLineBreak(r)
+	if gc == gcLC {
+		switch {
+		case unicode.IsUpper(r):
+			return "Lu"
+		case unicode.IsTitle(r):
+			return "Lt"
+		}
+		return "Ll"
+	}
+	return generalCategoryNames[gc]
+}
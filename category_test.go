@@ -0,0 +1,56 @@
+package runeseg
+
+import "testing"
+
+// Test the general categories of various runes.
+func TestGeneralCategory(t *testing.T) {
+	for _, testCase := range []struct {
+		r        rune
+		expected string
+	}{
+		{'A', "Lu"},
+		{'z', "Ll"},
+		{0x00c9, "Lu"}, // É
+		{0x0101, "Ll"}, // ā, in a mixed-case range.
+		{0x0100, "Lu"}, // Ā, in a mixed-case range.
+		{0x01c5, "Lt"}, // ǅ
+		{0x4e16, "Lo"},
+		{0x02b0, "Lm"},
+		{'7', "Nd"},
+		{0x0661, "Nd"}, // Arabic-Indic digit one.
+		{0x00bd, "No"}, // ½
+		{0x2160, "Nl"}, // Roman numeral one.
+		{0x00ab, "Pi"}, // «
+		{0x201c, "Pi"}, // “
+		{0x2018, "Pi"}, // ‘
+		{0x00bb, "Pf"}, // »
+		{0x201d, "Pf"}, // ”
+		{0x2019, "Pf"}, // ’
+		{'(', "Ps"},
+		{')', "Pe"},
+		{'-', "Pd"},
+		{'_', "Pc"},
+		{'!', "Po"},
+		{'+', "Sm"},
+		{'$', "Sc"},
+		{'^', "Sk"},
+		{0x00a9, "So"}, // ©
+		{0x0301, "Mn"}, // Combining acute accent.
+		{0x093f, "Mc"}, // Devanagari vowel sign i.
+		{0x20dd, "Me"}, // Combining enclosing circle.
+		{' ', "Zs"},
+		{0x3000, "Zs"},
+		{0x2028, "Zl"},
+		{0x2029, "Zp"},
+		{'\n', "Cc"},
+		{0x200d, "Cf"},
+		{0xe000, "Co"},
+		{0x0378, "Cn"}, // Unassigned.
+		{-1, "Cn"},
+		{0x110000, "Cn"},
+	} {
+		if actual := GeneralCategory(testCase.r); actual != testCase.expected {
+			t.Errorf("GeneralCategory(%U) is %q, expected %q", testCase.r, actual, testCase.expected)
+		}
+	}
+}
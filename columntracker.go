@@ -0,0 +1,106 @@
+package runeseg
+
+import "unicode/utf8"
+
+// ColumnTracker keeps track of the monospace column at which a terminal's
+// cursor is located while output is written to it incrementally, e.g. when
+// rendering progress information. The output is passed to
+// [ColumnTracker.Write] in arbitrary chunks. Grapheme clusters and UTF-8
+// sequences may be split across chunks.
+//
+// Because the grapheme cluster at the end of a chunk may be continued by the
+// next chunk (e.g. by a combining mark or a zero-width joiner), it is only
+// accounted for when the next chunk shows where it ends or when
+// [ColumnTracker.Flush] is called. Carriage returns and line feeds move the
+// column back to 0. Widths are calculated as with [StringWidth].
+//
+// The zero value is a tracker at column 0, ready to use.
+type ColumnTracker struct {
+	// The current column, not including the pending bytes.
+	column int
+
+	// The bytes written but not yet accounted for: the last grapheme cluster
+	// and possibly an incomplete UTF-8 sequence.
+	pending []byte
+}
+
+// Write processes the given bytes and returns the number of columns the
+// cursor advanced, i.e. the change in [ColumnTracker.Column]. This is the sum
+// of the widths of the grapheme clusters completed by these bytes. It is
+// negative if a line break moved the cursor back to column 0.
+func (c *ColumnTracker) Write(b []byte) (advance int) {
+	c.pending = append(c.pending, b...)
+
+	// Hold back an incomplete UTF-8 sequence at the end.
+	complete := len(c.pending)
+	for i := complete - 1; i >= 0 && i >= complete-utf8.UTFMax+1; i-- {
+		if utf8.RuneStart(c.pending[i]) {
+			if !utf8.FullRune(c.pending[i:]) {
+				complete = i
+			}
+			break
+		}
+	}
+
+	// Account for all grapheme clusters except the last one.
+	var (
+		cluster, rest []byte
+		width, pos    int
+	)
+	rest, state := c.pending[:complete], -1
+	for len(rest) > 0 {
+		cluster, rest, width, state = FirstGraphemeCluster(rest, state)
+		if len(rest) == 0 {
+			break // The last cluster may not be complete yet.
+		}
+		advance += c.advance(cluster, width)
+		pos += len(cluster)
+	}
+	c.pending = append(c.pending[:0], c.pending[pos:]...)
+	return
+}
+
+// Flush accounts for the grapheme cluster at the end of the bytes written so
+// far, assuming that it is complete, and returns the number of columns the
+// cursor advanced, as [ColumnTracker.Write] does. Incomplete UTF-8 sequences
+// are treated as one invalid rune each.
+func (c *ColumnTracker) Flush() (advance int) {
+	rest, state := c.pending, -1
+	for len(rest) > 0 {
+		var (
+			cluster []byte
+			width   int
+		)
+		cluster, rest, width, state = FirstGraphemeCluster(rest, state)
+		advance += c.advance(cluster, width)
+	}
+	c.pending = c.pending[:0]
+	return
+}
+
+// Column returns the current column of the cursor, i.e. the width of the text
+// written since the last line break, starting at 0. Bytes of a grapheme
+// cluster which may not be complete yet are not included.
+func (c *ColumnTracker) Column() int {
+	return c.column
+}
+
+// Reset moves the tracker back to column 0 and discards any bytes not yet
+// accounted for.
+func (c *ColumnTracker) Reset() {
+	c.column = 0
+	c.pending = c.pending[:0]
+}
+
+// advance moves the column past the given grapheme cluster with the given
+// width and returns the change in the column.
+func (c *ColumnTracker) advance(cluster []byte, width int) int {
+	switch cluster[0] {
+	case '\r', '\n':
+		advance := -c.column
+		c.column = 0
+		return advance
+	}
+	c.column += width
+	return width
+}
@@ -0,0 +1,98 @@
+package runeseg
+
+import "testing"
+
+// Test the ColumnTracker with output written in chunks.
+func TestColumnTracker(t *testing.T) {
+	for _, testCase := range []struct {
+		chunks   []string
+		advances []int // One per chunk, and one for the final Flush.
+		column   int
+	}{
+		{[]string{"abc", "de"}, []int{2, 2, 1}, 5},
+		{[]string{"\U0001F468\u200d", "\U0001F469\u200d\U0001F467", "!"}, []int{0, 0, 2, 1}, 3}, // ZWJ sequence split across writes.
+		{[]string{"\U0001F468", "\u200d\U0001F469", ""}, []int{0, 0, 0, 2}, 2},
+		{[]string{"e", "\u0301", "x"}, []int{0, 0, 1, 1}, 2},                                 // Combining mark in the next chunk.
+		{[]string{"a\xe4", "\xb8", "\x96b"}, []int{0, 0, 3, 1}, 4},                           // UTF-8 sequence split across writes.
+		{[]string{"\U0001F1E9", "\U0001F1EA\U0001F1EB", "\U0001F1F7"}, []int{0, 2, 0, 2}, 4}, // Regional indicators.
+		{[]string{"50%", "\r", "100%\n"}, []int{2, 1, 1, -4}, 0},                             // Carriage returns and line feeds.
+		{[]string{"ab\r", "\n\u4e16"}, []int{2, -2, 2}, 2},                                   // CR LF split across writes.
+		{[]string{"a\xe4"}, []int{0, 2}, 2},                                                  // Incomplete UTF-8 sequence.
+	} {
+		var (
+			c        ColumnTracker
+			advances []int
+			total    int
+		)
+		for _, chunk := range testCase.chunks {
+			advance := c.Write([]byte(chunk))
+			advances = append(advances, advance)
+			total += advance
+			if c.Column() != total {
+				t.Errorf("%q: column is %d after chunk %q, expected %d", testCase.chunks, c.Column(), chunk, total)
+			}
+		}
+		advances = append(advances, c.Flush())
+		if len(advances) != len(testCase.advances) {
+			t.Errorf("%q: got advances %v, expected %v", testCase.chunks, advances, testCase.advances)
+			continue
+		}
+		for i := range advances {
+			if advances[i] != testCase.advances[i] {
+				t.Errorf("%q: got advances %v, expected %v", testCase.chunks, advances, testCase.advances)
+				break
+			}
+		}
+		if c.Column() != testCase.column {
+			t.Errorf("%q: final column is %d, expected %d", testCase.chunks, c.Column(), testCase.column)
+		}
+	}
+}
+
+// Test that ColumnTracker agrees with StringWidth.
+func TestColumnTrackerStringWidth(t *testing.T) {
+	for _, testCase := range testCases {
+		b := []byte(testCase.original)
+		for size := 1; size <= 4; size++ {
+			var (
+				c     ColumnTracker
+				width int
+			)
+			for i := 0; i < len(b); i += size {
+				end := i + size
+				if end > len(b) {
+					end = len(b)
+				}
+				width += c.Write(b[i:end])
+			}
+			width += c.Flush()
+			var expected int
+			for _, cluster := range testCase.expected {
+				if len(cluster) > 0 && (cluster[0] == '\r' || cluster[0] == '\n') {
+					expected = 0
+					continue
+				}
+				expected += StringWidth(string(cluster))
+			}
+			if width != expected || c.Column() != expected {
+				t.Errorf("%q in chunks of %d: got width %d and column %d, expected %d", testCase.original, size, width, c.Column(), expected)
+			}
+		}
+	}
+}
+
+// Test resetting the ColumnTracker.
+func TestColumnTrackerReset(t *testing.T) {
+	var c ColumnTracker
+	c.Write([]byte("abc\U0001F468"))
+	c.Reset()
+	if advance := c.Write([]byte("\u200d\U0001F469x")); advance != 2 { // The discarded emoji is not joined.
+		t.Errorf("Expected advance 2 after Reset, got %d", advance)
+	}
+	if advance := c.Flush(); advance != 1 {
+		t.Errorf("Expected Flush advance 1, got %d", advance)
+	}
+	if c.Column() != 3 {
+		t.Errorf("Expected column 3, got %d", c.Column())
+	}
+}
@@ -26,7 +26,9 @@ For simple use cases:
 
 For iteration:
   - [Step] / [StepString] - Process text with all boundary info (recommended)
+  - [StepWithOptions] / [StepStringWithOptions] - Like Step, configured with [Options]
   - [Graphemes] - Convenient iterator class
+  - [GraphemesSeq] / [WordsSeq] / [SentencesSeq] / [LineSegmentsSeq] - Range-over-func iterators (Go 1.23+)
 
 For specific boundaries only:
   - [FirstGraphemeCluster] / [FirstGraphemeClusterInString]
@@ -45,6 +47,7 @@ the family emoji 👨‍👩‍👧‍👦 appears as one character but contains
 	runeseg.GraphemeClusterCount("👨‍👩‍👧‍👦") // 1 (what users see)
 
 The [Graphemes] class and related functions correctly handle these cases.
+[GraphemeBoundaries] returns the offsets of all grapheme cluster boundaries.
 
 # Word Boundaries
 
@@ -55,6 +58,7 @@ Word boundaries are used for:
   - Database proximity queries
 
 Use [FirstWord], [FirstWordInString], or check [Graphemes.IsWordBoundary].
+[AppendWordBoundaries] collects the offsets of all word boundaries at once.
 
 # Sentence Boundaries
 
@@ -64,6 +68,7 @@ Sentence boundaries are used for:
   - NLP sentence tokenization
 
 Use [FirstSentence], [FirstSentenceInString], or check [Graphemes.IsSentenceBoundary].
+[AppendSentenceBoundaries] collects the offsets of all sentence boundaries at once.
 
 # Line Breaking
 
@@ -75,6 +80,20 @@ The package distinguishes:
 
 Use [FirstLineSegment], [FirstLineSegmentInString], or check [Graphemes.LineBreak].
 The [Step] function is preferred as it respects grapheme cluster boundaries.
+[SplitLines] collects all segments of a string at once, and
+[AppendLineBreakOpportunities] collects their offsets.
+[NormalizeLineEndings] replaces all mandatory line break characters with a
+single form such as "\n".
+
+Mathematical operators do not provide break opportunities between their
+operands: U+2212 MINUS SIGN (class PR) as well as U+00D7 MULTIPLICATION SIGN
+and U+00F7 DIVISION SIGN (class AI, resolved to AL) keep "a−b", "3×4", and
+"6÷2" together. Surround operators with spaces to allow breaks after them.
+Note that × and ÷ are East Asian Ambiguous and therefore have a width of
+[EastAsianAmbiguousWidth].
+
+To wrap text to a given monospace width, use [WrapString] or the
+[WordWrapper] iterator.
 
 # Monospace Width
 
@@ -91,6 +110,18 @@ with [EastAsianAmbiguousWidth].
 Note: Actual rendering depends on your terminal/font. These calculations
 follow common conventions but may not match all environments.
 
+# Invalid UTF-8
+
+All functions accept invalid UTF-8. Like the standard library's utf8 package,
+they decode each byte which is not part of a valid UTF-8 sequence (for example
+a lone continuation byte or the first bytes of a truncated sequence) as a
+replacement character U+FFFD, consuming exactly one byte. Valid bytes
+following an invalid byte are never consumed along with it. Consequently, each
+invalid byte forms its own grapheme cluster (which may only be extended by
+combining marks following it), has a monospace width of 1 (unlike an actual
+U+FFFD, which is East Asian Ambiguous), and behaves like a letter (class AL)
+for line breaking. Use [Valid] to check a string beforehand.
+
 # Migrating from uniseg
 
 This package is a drop-in replacement for github.com/rivo/uniseg. The API is
@@ -2689,3 +2689,11 @@ var eastAsianWidth = [][3]int{
 	{0xF0000, 0xFFFFD, prA},   // Co [65534] <private-use-F0000>..<private-use-FFFFD>
 	{0x100000, 0x10FFFD, prA}, // Co [65534] <private-use-100000>..<private-use-10FFFD>
 }
+
+// eastAsianWidthVersion is the Unicode version of the data file
+// eastAsianWidth was generated from.
+// eastAsianWidthDate is the date of the generation.
+const (
+	eastAsianWidthVersion = "17.0.0"
+	eastAsianWidthDate    = "2026-01-05"
+)
@@ -307,3 +307,11 @@ var emojiPresentation = [][3]int{
 	{0x1FAF0, 0x1FAF6, prEmojiPresentation}, // E14.0  [7] (🫰..🫶)    hand with index finger and thumb crossed..heart hands
 	{0x1FAF7, 0x1FAF8, prEmojiPresentation}, // E15.0  [2] (🫷..🫸)    leftwards pushing hand..rightwards pushing hand
 }
+
+// emojiPresentationVersion is the Unicode version of the data file
+// emojiPresentation was generated from.
+// emojiPresentationDate is the date of the generation.
+const (
+	emojiPresentationVersion = "17.0.0"
+	emojiPresentationDate    = "2026-01-05"
+)
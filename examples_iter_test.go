@@ -0,0 +1,63 @@
+//go:build go1.23
+
+package runeseg_test
+
+import (
+	"fmt"
+
+	"github.com/scalecode-solutions/runeseg"
+)
+
+func ExampleGraphemes_Iterate() {
+	g := runeseg.NewGraphemes("🇩🇪🏳️‍🌈!")
+	for cluster := range g.Iterate() {
+		fmt.Println(cluster, g.Width())
+	}
+	// Output: 🇩🇪 2
+	//🏳️‍🌈 2
+	//! 1
+}
+
+func ExampleGraphemesSeq() {
+	for cluster := range runeseg.GraphemesSeq("🇩🇪🏳️‍🌈!") {
+		fmt.Println(cluster)
+	}
+	// Output: 🇩🇪
+	//🏳️‍🌈
+	//!
+}
+
+func ExampleWordsSeq() {
+	for word := range runeseg.WordsSeq("Hello, world!") {
+		fmt.Printf("(%s)\n", word)
+	}
+	// Output: (Hello)
+	//(,)
+	//( )
+	//(world)
+	//(!)
+}
+
+func ExampleSentencesSeq() {
+	for sentence := range runeseg.SentencesSeq("This is sentence 1.0. And this is sentence two.") {
+		fmt.Printf("(%s)\n", sentence)
+	}
+	// Output: (This is sentence 1.0. )
+	//(And this is sentence two.)
+}
+
+func ExampleLineSegmentsSeq() {
+	for segment, lineBreak := range runeseg.LineSegmentsSeq("First line.\nSecond line.") {
+		fmt.Printf("(%s)", segment)
+		if lineBreak == runeseg.LineMustBreak {
+			fmt.Println(" < must break")
+		} else {
+			fmt.Println(" < may break")
+		}
+	}
+	// Output: (First ) < may break
+	//(line.
+	//) < must break
+	//(Second ) < may break
+	//(line.) < must break
+}
@@ -273,6 +273,28 @@ func ExampleStepString_lineBreaking() {
 	//‖Second |line.‖
 }
 
+func ExampleLineBreakOptions_StepString() {
+	options := runeseg.LineBreakOptions{BreakBeforeSpaces: true}
+	str := "First line.\nSecond line."
+	state := -1
+	var (
+		c          string
+		boundaries int
+	)
+	for len(str) > 0 {
+		c, str, boundaries, state = options.StepString(str, state)
+		fmt.Print(c)
+		switch boundaries & runeseg.MaskLine {
+		case runeseg.LineCanBreak:
+			fmt.Print("|")
+		case runeseg.LineMustBreak:
+			fmt.Print("‖")
+		}
+	}
+	// Output: First| line.
+	//‖Second| line.‖
+}
+
 func ExampleGraphemes_graphemes() {
 	g := runeseg.NewGraphemes("🇩🇪🏳️‍🌈")
 	for g.Next() {
@@ -282,6 +304,17 @@ func ExampleGraphemes_graphemes() {
 	//🏳️‍🌈
 }
 
+func ExampleGraphemes_All() {
+	g := runeseg.NewGraphemes("🇩🇪🏳️‍🌈!")
+	fmt.Println(len(g.All()))
+	// Output: 3
+}
+
+func ExampleAllGraphemes() {
+	fmt.Printf("%q\n", runeseg.AllGraphemes("Käse\r\n"))
+	// Output: ["K" "ä" "s" "e" "\r\n"]
+}
+
 func ExampleGraphemes_word() {
 	g := runeseg.NewGraphemes("Hello, world!")
 	for g.Next() {
@@ -293,6 +326,18 @@ func ExampleGraphemes_word() {
 	// Output: Hello|,| |world|!|
 }
 
+func ExampleGraphemes_IsWordStart() {
+	g := runeseg.NewGraphemes("Hello, world!")
+	for g.Next() {
+		if g.IsWordStart() {
+			fmt.Print("[" + g.Str() + "]")
+		} else {
+			fmt.Print(g.Str())
+		}
+	}
+	// Output: [H]ello[,][ ][w]orld[!]
+}
+
 func ExampleGraphemes_sentence() {
 	g := runeseg.NewGraphemes("This is sentence 1.0. And this is sentence two.")
 	for g.Next() {
@@ -327,7 +372,67 @@ func ExampleGraphemes_lineBreaking() {
 	//Iterator has been reset. LineDontBreak
 }
 
+func ExampleSegmenter_graphemes() {
+	s := runeseg.NewSegmenter("🇩🇪🏳️‍🌈", runeseg.BoundaryGrapheme)
+	for s.Next() {
+		fmt.Println(s.Text())
+	}
+	// Output: 🇩🇪
+	//🏳️‍🌈
+}
+
+func ExampleSegmenter_word() {
+	s := runeseg.NewSegmenter("Hello, world!", runeseg.BoundaryWord)
+	for s.Next() {
+		fmt.Printf("(%s)\n", s.Text())
+	}
+	// Output: (Hello)
+	//(,)
+	//( )
+	//(world)
+	//(!)
+}
+
+func ExampleSegmenter_sentence() {
+	s := runeseg.NewSegmenter("This is sentence 1.0. And this is sentence two.", runeseg.BoundarySentence)
+	for s.Next() {
+		fmt.Printf("(%s)\n", s.Text())
+	}
+	// Output: (This is sentence 1.0. )
+	//(And this is sentence two.)
+}
+
+func ExampleSegmenter_lineBreaking() {
+	s := runeseg.NewSegmenter("First line.\nSecond line.", runeseg.BoundaryLine)
+	for s.Next() {
+		fmt.Printf("(%s)", s.Text())
+		if s.MustBreak() {
+			fmt.Println(" < must break")
+		} else {
+			fmt.Println(" < may break")
+		}
+	}
+	// Output: (First ) < may break
+	//(line.
+	//) < must break
+	//(Second ) < may break
+	//(line.) < must break
+}
+
 func ExampleStringWidth() {
 	fmt.Println(runeseg.StringWidth("Hello, 世界"))
 	// Output: 11
 }
+
+func ExampleWrapString() {
+	lines := runeseg.WrapString("- Wrapped lines of a list item are aligned with its text.", 20, runeseg.WrapOptions{
+		HangingIndent: "  ",
+	})
+	for _, line := range lines {
+		fmt.Println(line)
+	}
+	// Output: - Wrapped lines of a
+	//   list item are
+	//   aligned with its
+	//   text.
+}
@@ -0,0 +1,89 @@
+package runeseg
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// FoldFullwidth maps the fullwidth forms of the Unicode "Halfwidth and
+// Fullwidth Forms" block to their canonical counterparts, for example "ＡＢＣ"
+// to "ABC" or "１２３" to "123", and U+3000 IDEOGRAPHIC SPACE to a regular
+// space. This is useful to normalize text before measuring its width (see
+// [StringWidth]) because fullwidth forms are twice as wide as their
+// counterparts.
+//
+// This is a compatibility fold similar to, but not the same as, Unicode
+// normalization form NFKC: Only single runes are mapped and all other runes,
+// including halfwidth Katakana and Hangul, are left unchanged. Invalid UTF-8 is
+// also left unchanged. If nothing needs to be folded, "s" is returned as is.
+func FoldFullwidth(s string) string {
+	var b strings.Builder
+	for index := 0; index < len(s); {
+		r, length := utf8.DecodeRuneInString(s[index:])
+		folded := foldFullwidthRune(r)
+		if folded != r && b.Cap() == 0 {
+			b.Grow(len(s))
+			b.WriteString(s[:index])
+		}
+		if folded != r {
+			b.WriteRune(folded)
+		} else if b.Cap() > 0 {
+			b.WriteString(s[index : index+length])
+		}
+		index += length
+	}
+	if b.Cap() == 0 {
+		return s
+	}
+	return b.String()
+}
+
+// foldFullwidthRune returns the counterpart of the given fullwidth rune or the
+// rune itself if it's not a fullwidth form.
+func foldFullwidthRune(r rune) rune {
+	switch {
+	case r >= 0xff01 && r <= 0xff5e: // FULLWIDTH EXCLAMATION MARK to FULLWIDTH TILDE.
+		return r - 0xff01 + '!'
+	case r == 0x3000: // IDEOGRAPHIC SPACE.
+		return ' '
+	}
+	switch r {
+	case 0xff5f: // FULLWIDTH LEFT WHITE PARENTHESIS.
+		return 0x2985
+	case 0xff60: // FULLWIDTH RIGHT WHITE PARENTHESIS.
+		return 0x2986
+	case 0xffe0: // FULLWIDTH CENT SIGN.
+		return 0xa2
+	case 0xffe1: // FULLWIDTH POUND SIGN.
+		return 0xa3
+	case 0xffe2: // FULLWIDTH NOT SIGN.
+		return 0xac
+	case 0xffe3: // FULLWIDTH MACRON.
+		return 0xaf
+	case 0xffe4: // FULLWIDTH BROKEN BAR.
+		return 0xa6
+	case 0xffe5: // FULLWIDTH YEN SIGN.
+		return 0xa5
+	case 0xffe6: // FULLWIDTH WON SIGN.
+		return 0x20a9
+	}
+	return r
+}
+
+// EqualFoldWord reports whether the words a and b, e.g. two word segments
+// returned by [FirstWordInString], are equal under Unicode simple case
+// folding, for example "Hello" and "HELLO" or "Σίσυφος" and "ΣΊΣΥΦΟΣ". It is
+// equivalent to [strings.EqualFold] and meant for simple, case-insensitive
+// searches for words.
+//
+// Simple case folding maps each rune to a single rune. Full case folding,
+// which maps some runes to several runes, is not applied. Hence, "STRASSE"
+// matches "strasse" but not "straße". The folding is also independent of the
+// language: It doesn't apply the Turkish and Azeri rules, where the uppercase
+// form of "i" is "İ" (U+0130) and the lowercase form of "I" is "ı" (U+0131).
+// So "I" matches "i" while "İ" and "ı" only match themselves. Finally, the
+// words are not normalized, so a precomposed "é" does not match an "e"
+// followed by a combining acute accent.
+func EqualFoldWord(a, b string) bool {
+	return strings.EqualFold(a, b)
+}
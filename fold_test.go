@@ -0,0 +1,63 @@
+package runeseg
+
+import "testing"
+
+// Test the folding of fullwidth forms.
+func TestFoldFullwidth(t *testing.T) {
+	for _, testCase := range []struct {
+		original, expected string
+	}{
+		{"", ""},
+		{"abc", "abc"},
+		{"ＡＢＣ", "ABC"},             // Letters.
+		{"ａｚ", "az"},               // Lowercase letters.
+		{"０１９", "019"},             // Digits.
+		{"！（），．？～", "!(),.?~"},     // Punctuation.
+		{"￠￡￥￦", "¢£¥₩"},           // Currency signs.
+		{"｟｠", "⦅⦆"},               // White parentheses.
+		{"\u3000", " "},            // Ideographic space.
+		{"aＢc", "aBc"},             // Mixed.
+		{"世Ａ界", "世A界"},             // Wide characters are kept.
+		{"ｶﾞ", "ｶﾞ"},               // Halfwidth katakana are kept.
+		{"ﾡ", "ﾡ"},                 // Halfwidth Hangul is kept.
+		{"\xffＡ\xe2", "\xffA\xe2"}, // Invalid UTF-8 is kept.
+	} {
+		folded := FoldFullwidth(testCase.original)
+		if folded != testCase.expected {
+			t.Errorf("%q folded to %q, expected %q", testCase.original, folded, testCase.expected)
+		}
+	}
+	if w, folded := StringWidth("ＡＢＣ"), StringWidth(FoldFullwidth("ＡＢＣ")); w != 6 || folded != 3 {
+		t.Errorf("Expected widths 6 and 3, got %d and %d", w, folded)
+	}
+}
+
+// Test the case-insensitive comparison of words.
+func TestEqualFoldWord(t *testing.T) {
+	for _, testCase := range []struct {
+		a, b     string
+		expected bool
+	}{
+		{"", "", true},
+		{"Hello", "hELLO", true},
+		{"Hello", "Hell", false},
+		{"STRASSE", "strasse", true},
+		{"STRASSE", "straße", false}, // Full case folding is not applied.
+		{"straße", "STRAẞE", true},   // U+1E9E folds to ß.
+		{"Σίσυφος", "ΣΊΣΥΦΟΣ", true}, // Final sigma.
+		{"Ǆ", "ǅ", true},             // Titlecase.
+		{"\u212a", "k", true},        // KELVIN SIGN.
+		{"ISTANBUL", "istanbul", true},
+		{"İSTANBUL", "istanbul", false}, // No Turkish rules: İ only matches itself.
+		{"ıSPARTA", "isparta", false},   // No Turkish rules: ı only matches itself.
+		{"\u0130", "i\u0307", false},    // Not normalized.
+		{"café", "CAFÉ", true},
+		{"caf\u00e9", "cafe\u0301", false}, // Not normalized.
+		{"ＡＢＣ", "ａｂｃ", true},               // Fullwidth letters fold among themselves.
+		{"ＡＢＣ", "abc", false},
+	} {
+		if result := EqualFoldWord(testCase.a, testCase.b); result != testCase.expected {
+			t.Errorf("EqualFoldWord(%q, %q) is %t, expected %t", testCase.a, testCase.b, result, testCase.expected)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+//go:build generate
+
+// This program generates a property file for the Default_Ignorable_Code_Point
+// property from the Unicode Character Database DerivedCoreProperties.txt file.
+//
+//go:generate go run gen_ignorable.go
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ignorableURL = `https://www.unicode.org/Public/17.0.0/ucd/DerivedCoreProperties.txt`
+)
+
+// The regular expression for the first line of DerivedCoreProperties.txt which
+// contains the file's version, e.g. "# DerivedCoreProperties-17.0.0.txt".
+var headerVersionPattern = regexp.MustCompile(`^#\s*DerivedCoreProperties-(\d+\.\d+\.\d+)\.txt$`)
+
+// The regular expression for a line containing the Default_Ignorable_Code_Point
+// property.
+var ignorablePattern = regexp.MustCompile(`^([0-9A-F]{4,6})(\.\.([0-9A-F]{4,6}))?\s*;\s*Default_Ignorable_Code_Point\s*#\s*(.+)$`)
+
+func main() {
+	log.SetPrefix("gen_ignorable: ")
+	log.SetFlags(0)
+
+	src, err := parse()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Format the Go code.
+	formatted, err := format.Source([]byte(src))
+	if err != nil {
+		log.Fatal("gofmt:", err)
+	}
+
+	// Save it to the target file.
+	log.Print("Writing to ignorableproperties.go")
+	if err := os.WriteFile("ignorableproperties.go", formatted, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func parse() (string, error) {
+	log.Printf("Parsing %s", ignorableURL)
+	res, err := http.Get(ignorableURL)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	// Temporary buffer to hold properties.
+	var (
+		properties [][3]string
+		version    string
+	)
+
+	// Parse the file.
+	scanner := bufio.NewScanner(res.Body)
+	num := 0
+	for scanner.Scan() {
+		num++
+		line := strings.TrimSpace(scanner.Text())
+
+		// The first line contains the file's version.
+		if num == 1 {
+			match := headerVersionPattern.FindStringSubmatch(line)
+			if match == nil {
+				return "", errors.New("no Unicode version found in file header")
+			}
+			version = match[1]
+		}
+
+		// Skip comments and empty lines.
+		if strings.HasPrefix(line, "#") || line == "" {
+			continue
+		}
+
+		// Check if this is a Default_Ignorable_Code_Point line.
+		fields := ignorablePattern.FindStringSubmatch(line)
+		if fields == nil {
+			continue
+		}
+		from, to := fields[1], fields[3]
+		if to == "" {
+			to = from
+		}
+		properties = append(properties, [3]string{from, to, fields[4]})
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	if len(properties) == 0 {
+		return "", errors.New("no Default_Ignorable_Code_Point properties found")
+	}
+
+	// Avoid overflow during binary search.
+	if len(properties) >= 1<<31 {
+		return "", errors.New("too many properties")
+	}
+
+	// Sort properties.
+	sort.Slice(properties, func(i, j int) bool {
+		left, _ := strconv.ParseUint(properties[i][0], 16, 64)
+		right, _ := strconv.ParseUint(properties[j][0], 16, 64)
+		return left < right
+	})
+
+	// Header.
+	var buf bytes.Buffer
+	now := time.Now()
+	buf.WriteString(`// Code generated via go generate from gen_ignorable.go. DO NOT EDIT.
+
+package runeseg
+
+// defaultIgnorableCodePoints are taken from
+// ` + ignorableURL + `
+// on ` + now.Format("January 2, 2006") + `. See https://www.unicode.org/license.html for the Unicode
+// license agreement.
+var defaultIgnorableCodePoints = [][3]int{
+`)
+
+	// Properties.
+	for _, prop := range properties {
+		fmt.Fprintf(&buf, "\t{0x%s, 0x%s, prDefaultIgnorable}, // %s\n", prop[0], prop[1], prop[2])
+	}
+
+	// Tail.
+	buf.WriteString(`}
+
+// defaultIgnorableCodePointsVersion is the Unicode version of the data file
+// defaultIgnorableCodePoints was generated from.
+// defaultIgnorableCodePointsDate is the date of the generation.
+const (
+	defaultIgnorableCodePointsVersion = "` + version + `"
+	defaultIgnorableCodePointsDate    = "` + now.Format("2006-01-02") + `"
+)
+`)
+
+	return buf.String(), nil
+}
@@ -27,6 +27,10 @@ const (
 	incbURL = `https://www.unicode.org/Public/17.0.0/ucd/DerivedCoreProperties.txt`
 )
 
+// The regular expression for the first line of DerivedCoreProperties.txt which
+// contains the file's version, e.g. "# DerivedCoreProperties-17.0.0.txt".
+var headerVersionPattern = regexp.MustCompile(`^#\s*DerivedCoreProperties-(\d+\.\d+\.\d+)\.txt$`)
+
 // The regular expression for a line containing an InCB property.
 var incbPattern = regexp.MustCompile(`^([0-9A-F]{4,6})(\.\.([0-9A-F]{4,6}))?\s*;\s*InCB\s*;\s*(\w+)\s*#\s*(.+)$`)
 
@@ -61,7 +65,10 @@ func parse() (string, error) {
 	defer res.Body.Close()
 
 	// Temporary buffer to hold properties.
-	var properties [][4]string
+	var (
+		properties [][4]string
+		version    string
+	)
 
 	// Parse the file.
 	scanner := bufio.NewScanner(res.Body)
@@ -70,6 +77,15 @@ func parse() (string, error) {
 		num++
 		line := strings.TrimSpace(scanner.Text())
 
+		// The first line contains the file's version.
+		if num == 1 {
+			match := headerVersionPattern.FindStringSubmatch(line)
+			if match == nil {
+				return "", errors.New("no Unicode version found in file header")
+			}
+			version = match[1]
+		}
+
 		// Skip comments and empty lines.
 		if strings.HasPrefix(line, "#") || line == "" {
 			continue
@@ -105,13 +121,14 @@ func parse() (string, error) {
 
 	// Header.
 	var buf bytes.Buffer
+	now := time.Now()
 	buf.WriteString(`// Code generated via go generate from gen_incb.go. DO NOT EDIT.
 
 package runeseg
 
 // incbCodePoints are taken from
 // ` + incbURL + `
-// on ` + time.Now().Format("January 2, 2006") + `. See https://www.unicode.org/license.html for the Unicode
+// on ` + now.Format("January 2, 2006") + `. See https://www.unicode.org/license.html for the Unicode
 // license agreement.
 var incbCodePoints = [][3]int{
 `)
@@ -123,7 +140,16 @@ var incbCodePoints = [][3]int{
 	}
 
 	// Tail.
-	buf.WriteString("}\n")
+	buf.WriteString(`}
+
+// incbCodePointsVersion is the Unicode version of the data file
+// incbCodePoints was generated from.
+// incbCodePointsDate is the date of the generation.
+const (
+	incbCodePointsVersion = "` + version + `"
+	incbCodePointsDate    = "` + now.Format("2006-01-02") + `"
+)
+`)
 
 	return buf.String(), nil
 }
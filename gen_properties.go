@@ -44,6 +44,14 @@ const (
 	emojiURL    = `https://unicode.org/Public/17.0.0/ucd/emoji/emoji-data.txt`
 )
 
+// The regular expression for the Unicode version in a URL of the Unicode
+// Character Database.
+var urlVersionPattern = regexp.MustCompile(`/Public/(\d+\.\d+\.\d+)/`)
+
+// The regular expression for the first line of a Unicode properties text file
+// which contains the file's name and version, e.g. "# LineBreak-17.0.0.txt".
+var headerVersionPattern = regexp.MustCompile(`^#\s*[\w-]+-(\d+\.\d+\.\d+)\.txt$`)
+
 // The regular expression for a line containing a code point range property.
 var propertyPattern = regexp.MustCompile(`^([0-9A-F]{4,6})(\.\.([0-9A-F]{4,6}))?\s*;\s*([A-Za-z0-9_]+)\s*#\s(.+)$`)
 
@@ -108,6 +116,21 @@ func parse(propertyURL, emojiProperty string, includeGeneralCategory bool) (stri
 	// Temporary buffer to hold properties.
 	var properties [][4]string
 
+	// Determine the Unicode version from the URLs. It is verified against the
+	// files' headers below.
+	versionURL := propertyURL
+	if versionURL == "" {
+		versionURL = emojiURL
+	}
+	match := urlVersionPattern.FindStringSubmatch(versionURL)
+	if match == nil {
+		return "", fmt.Errorf("no Unicode version found in %s", versionURL)
+	}
+	version := match[1]
+	if match := urlVersionPattern.FindStringSubmatch(emojiURL); emojiProperty != "" && (match == nil || match[1] != version) {
+		return "", fmt.Errorf("Unicode version of %s does not match %s", emojiURL, version)
+	}
+
 	// Open the first URL.
 	if propertyURL != "" {
 		log.Printf("Parsing %s", propertyURL)
@@ -125,6 +148,17 @@ func parse(propertyURL, emojiProperty string, includeGeneralCategory bool) (stri
 			num++
 			line := strings.TrimSpace(scanner.Text())
 
+			// The first line contains the file's version.
+			if num == 1 {
+				match := headerVersionPattern.FindStringSubmatch(line)
+				if match == nil {
+					return "", fmt.Errorf("no Unicode version found in header of %s", propertyURL)
+				}
+				if match[1] != version {
+					return "", fmt.Errorf("%s has version %s, expected %s", propertyURL, match[1], version)
+				}
+			}
+
 			// Skip comments and empty lines.
 			if strings.HasPrefix(line, "#") || line == "" {
 				continue
@@ -170,6 +204,17 @@ func parse(propertyURL, emojiProperty string, includeGeneralCategory bool) (stri
 			num++
 			line := scanner.Text()
 
+			// The first line contains the file's version.
+			if num == 1 {
+				match := headerVersionPattern.FindStringSubmatch(strings.TrimSpace(line))
+				if match == nil {
+					return "", fmt.Errorf("no Unicode version found in header of %s", emojiURL)
+				}
+				if match[1] != version {
+					return "", fmt.Errorf("%s has version %s, expected %s", emojiURL, match[1], version)
+				}
+			}
+
 			// Skip comments, empty lines, and everything not containing
 			// "Extended_Pictographic".
 			if strings.HasPrefix(line, "#") || line == "" || !strings.Contains(line, emojiProperty) {
@@ -264,6 +309,7 @@ func parse(propertyURL, emojiProperty string, includeGeneralCategory bool) (stri
 		buf          bytes.Buffer
 		emojiComment string
 	)
+	now := time.Now()
 	columns := 3
 	if includeGeneralCategory {
 		columns = 4
@@ -280,7 +326,7 @@ package runeseg
 
 // ` + os.Args[3] + ` are taken from
 // ` + propertyURL + emojiComment + `
-// on ` + time.Now().Format("January 2, 2006") + `. See https://www.unicode.org/license.html for the Unicode
+// on ` + now.Format("January 2, 2006") + `. See https://www.unicode.org/license.html for the Unicode
 // license agreement.
 var ` + os.Args[3] + ` = [][` + strconv.Itoa(columns) + `]int{
 	`)
@@ -300,7 +346,15 @@ var ` + os.Args[3] + ` = [][` + strconv.Itoa(columns) + `]int{
 	}
 
 	// Tail.
-	buf.WriteString("}")
+	buf.WriteString(`}
+
+// ` + os.Args[3] + `Version is the Unicode version of the data file
+// ` + os.Args[3] + ` was generated from.
+// ` + os.Args[3] + `Date is the date of the generation.
+const (
+	` + os.Args[3] + `Version = "` + version + `"
+	` + os.Args[3] + `Date = "` + now.Format("2006-01-02") + `"
+)`)
 
 	return buf.String(), nil
 }
@@ -2,6 +2,7 @@ package runeseg
 
 import (
 	"fmt"
+	"strings"
 	"unicode/utf8"
 )
 
@@ -37,6 +38,11 @@ type Graphemes struct {
 	// The current boundary information of the [Step] parser.
 	boundaries int
 
+	// The boundary information before the current grapheme cluster, i.e. the
+	// boundary information of the previous cluster. At the start of the
+	// string, there are word and sentence boundaries.
+	previous int
+
 	// The current state of the [Step] parser.
 	state int
 }
@@ -71,6 +77,11 @@ func (g *Graphemes) Next() bool {
 		g.cluster = ""
 		return false
 	}
+	if g.state == -1 {
+		g.previous = MaskWord | MaskSentence
+	} else {
+		g.previous = g.boundaries
+	}
 	g.offset += len(g.cluster)
 	g.cluster, g.remaining, g.boundaries, g.state = StepString(g.remaining, g.state)
 	return true
@@ -137,6 +148,29 @@ func (g *Graphemes) IsSentenceBoundary() bool {
 	return g.boundaries&MaskSentence != 0
 }
 
+// IsWordStart returns true if a word begins with the current grapheme cluster,
+// i.e. if there is a word boundary before it. This is the case for the first
+// cluster of the string and for every cluster following one for which
+// [Graphemes.IsWordBoundary] returned true, e.g. to highlight the first
+// character of each word. If the iterator is already past the end or
+// [Graphemes.Next] has not yet been called, false is returned.
+func (g *Graphemes) IsWordStart() bool {
+	if g.state < 0 {
+		return false
+	}
+	return g.previous&MaskWord != 0
+}
+
+// IsSentenceStart returns true if a sentence begins with the current grapheme
+// cluster, i.e. if there is a sentence boundary before it. See
+// [Graphemes.IsWordStart] for details.
+func (g *Graphemes) IsSentenceStart() bool {
+	if g.state < 0 {
+		return false
+	}
+	return g.previous&MaskSentence != 0
+}
+
 // LineBreak returns whether the line can be broken after the current grapheme
 // cluster. A value of [LineDontBreak] means the line may not be broken, a value
 // of [LineMustBreak] means the line must be broken, and a value of
@@ -159,15 +193,76 @@ func (g *Graphemes) Width() int {
 	return g.boundaries >> ShiftWidth
 }
 
+// RemainingWidth returns the monospace width of the rest of the string, i.e.
+// of all grapheme clusters following the current one. Before the first call to
+// [Graphemes.Next], this is the width of the entire string. The width is
+// calculated anew with every call. The state of the iterator is not changed.
+func (g *Graphemes) RemainingWidth() int {
+	return StringWidth(g.remaining)
+}
+
+// BoundaryFlags returns the boundary information of the current grapheme
+// cluster in the same bit-packed format as the "boundaries" value returned by
+// [Step], i.e. the word, sentence, and line break information after the
+// cluster as well as its width. Use the [MaskWord], [MaskSentence], [MaskLine],
+// and [ShiftWidth] constants to extract the individual values. There is always
+// a grapheme cluster boundary after the current cluster.
+//
+// If the iterator is already past the end or [Graphemes.Next] has not yet been
+// called, the values correspond to those returned by [Graphemes.IsWordBoundary],
+// [Graphemes.IsSentenceBoundary], [Graphemes.LineBreak], and
+// [Graphemes.Width].
+func (g *Graphemes) BoundaryFlags() int {
+	if g.state < 0 {
+		return MaskWord | MaskSentence | g.LineBreak()
+	}
+	return g.boundaries
+}
+
 // Reset puts the iterator into its initial state such that the next call to
-// [Graphemes.Next] sets it to the first grapheme cluster again.
+// [Graphemes.Next] sets it to the first grapheme cluster again. All parser
+// states (grapheme, word, sentence, and line) as well as the boundary
+// information and width of the current cluster are discarded.
 func (g *Graphemes) Reset() {
 	g.state = -1
 	g.offset = 0
 	g.cluster = ""
+	g.boundaries = 0
+	g.previous = 0
 	g.remaining = g.original
 }
 
+// Seek positions the iterator such that the next call to [Graphemes.Next]
+// returns the first grapheme cluster which starts at or after the given byte
+// offset into the original string. Offsets inside a grapheme cluster are thus
+// moved to the end of that cluster. If the offset is negative or larger than
+// the length of the original string, the iterator is not changed and false is
+// returned.
+//
+// Because word, sentence, and line boundaries depend on the preceding text,
+// Seek does not jump to the offset directly but iterates over the text in
+// front of it. When seeking forward, it continues from the current position.
+// When seeking backward, it starts over from the beginning of the string. The
+// cost of a call is therefore proportional to the distance covered.
+func (g *Graphemes) Seek(byteOffset int) bool {
+	if byteOffset < 0 || byteOffset > len(g.original) {
+		return false
+	}
+	if g.state == -2 || byteOffset < g.offset+len(g.cluster) {
+		g.Reset()
+	}
+	for g.offset+len(g.cluster) < byteOffset && g.Next() {
+	}
+	return true
+}
+
+// All returns all grapheme clusters of the original string, regardless of the
+// current position of the iterator, which remains unchanged. This allocates a
+// new slice. To avoid this, iterate with [Graphemes.Next] instead.
+func (g *Graphemes) All() []string {
+	return AllGraphemes(g.original)
+}
+
 // GraphemeClusterCount returns the number of user-perceived characters
 // (grapheme clusters) for the given string.
 func GraphemeClusterCount(s string) (n int) {
@@ -179,6 +274,132 @@ func GraphemeClusterCount(s string) (n int) {
 	return
 }
 
+// CodePointCount returns the number of code points (runes) in the given
+// string. Invalid UTF-8 bytes count as one code point each. It is equivalent
+// to [utf8.RuneCountInString] and complements [GraphemeClusterCount], e.g.
+// to find out how many code points make up a string's grapheme clusters.
+func CodePointCount(s string) int {
+	return utf8.RuneCountInString(s)
+}
+
+// ClusterReport returns the length of the given string in bytes, its number
+// of code points (see [CodePointCount]) and grapheme clusters (see
+// [GraphemeClusterCount]), and its monospace width (see [StringWidth]). It
+// is useful for diagnosing surprising counts or widths and is faster than
+// calling these functions separately because the string is traversed only
+// once. For example, the family emoji, consisting of four person emoji joined
+// by three zero-width joiners, has 25 bytes, 7 code points, 1 grapheme
+// cluster, and a width of 2.
+func ClusterReport(s string) (bytes, codePoints, clusters, width int) {
+	bytes = len(s)
+	state := -1
+	for len(s) > 0 {
+		var (
+			cluster string
+			w       int
+		)
+		cluster, s, w, state = FirstGraphemeClusterInString(s, state)
+		codePoints += utf8.RuneCountInString(cluster)
+		clusters++
+		width += w
+	}
+	return
+}
+
+// GraphemeToRuneIndex returns the number of code points (runes) in the first
+// graphemeIndex grapheme clusters of the given string, i.e. the rune index at
+// which the grapheme cluster with the given zero-based index begins. This is
+// useful when integrating with APIs which count positions in runes. Negative
+// indices return 0 and indices at or beyond the number of grapheme clusters
+// (see [GraphemeClusterCount]) return the number of runes in the string (see
+// [CodePointCount]).
+func GraphemeToRuneIndex(s string, graphemeIndex int) (runeIndex int) {
+	var cluster string
+	state := -1
+	for index := 0; index < graphemeIndex && len(s) > 0; index++ {
+		cluster, s, _, state = FirstGraphemeClusterInString(s, state)
+		runeIndex += utf8.RuneCountInString(cluster)
+	}
+	return
+}
+
+// RuneToGraphemeIndex returns the zero-based index of the grapheme cluster
+// which contains the code point (rune) with the given zero-based index in the
+// given string. It is the inverse of [GraphemeToRuneIndex]. Rune indices
+// inside a grapheme cluster are snapped to the start of that cluster. Negative
+// indices return 0 and indices at or beyond the number of runes in the string
+// return the number of grapheme clusters.
+func RuneToGraphemeIndex(s string, runeIndex int) (graphemeIndex int) {
+	var (
+		cluster string
+		runes   int
+	)
+	state := -1
+	for len(s) > 0 {
+		cluster, s, _, state = FirstGraphemeClusterInString(s, state)
+		runes += utf8.RuneCountInString(cluster)
+		if runeIndex < runes {
+			return
+		}
+		graphemeIndex++
+	}
+	return
+}
+
+// AllGraphemes returns all grapheme clusters of the given string. This is a
+// convenient alternative to a loop over [FirstGraphemeClusterInString] for code
+// where allocations don't matter.
+func AllGraphemes(s string) []string {
+	var (
+		clusters []string
+		cluster  string
+	)
+	state := -1
+	for len(s) > 0 {
+		cluster, s, _, state = FirstGraphemeClusterInString(s, state)
+		clusters = append(clusters, cluster)
+	}
+	return clusters
+}
+
+// GraphemeBoundaries returns the byte offsets of all grapheme cluster
+// boundaries in the given string, including 0 and len(s). The clusters are
+// thus s[b[i]:b[i+1]] for consecutive offsets b[i] and b[i+1]. For an empty
+// string, the result is []int{0}.
+func GraphemeBoundaries(s string) []int {
+	var (
+		cluster string
+		offset  int
+	)
+	boundaries := []int{0}
+	state := -1
+	for len(s) > 0 {
+		cluster, s, _, state = FirstGraphemeClusterInString(s, state)
+		offset += len(cluster)
+		boundaries = append(boundaries, offset)
+	}
+	return boundaries
+}
+
+// GraphemeClusterCountRunes is like [GraphemeClusterCount] but for a slice of
+// runes, e.g. the result of a []rune(s) conversion. It does not encode the
+// runes in UTF-8.
+func GraphemeClusterCountRunes(runes []rune) (n int) {
+	if len(runes) == 0 {
+		return
+	}
+	state, _, _ := transitionGraphemeState(-1, runes[0])
+	n = 1
+	for _, r := range runes[1:] {
+		var boundary bool
+		state, _, boundary = transitionGraphemeState(state, r)
+		if boundary {
+			n++
+		}
+	}
+	return
+}
+
 // ReverseString reverses the given string while observing grapheme cluster
 // boundaries.
 func ReverseString(s string) string {
@@ -248,7 +469,7 @@ func FirstGraphemeCluster(b []byte, state int) (cluster, rest []byte, width, new
 		} else {
 			prop = state >> shiftGraphemePropState
 		}
-		return b, nil, runeWidth(r, prop), grAny | (prop << shiftGraphemePropState)
+		return b, nil, runeWidth(r, length, prop), grAny | (prop << shiftGraphemePropState)
 	}
 
 	// If we don't know the state, determine it now.
@@ -258,7 +479,7 @@ func FirstGraphemeCluster(b []byte, state int) (cluster, rest []byte, width, new
 	} else {
 		firstProp = state >> shiftGraphemePropState
 	}
-	width += runeWidth(r, firstProp)
+	width += runeWidth(r, length, firstProp)
 
 	// Transition until we find a boundary.
 	for {
@@ -282,7 +503,7 @@ func FirstGraphemeCluster(b []byte, state int) (cluster, rest []byte, width, new
 				width = 2
 			}
 		} else if firstProp != prRegionalIndicator && firstProp != prL {
-			width += runeWidth(r, prop)
+			width += runeWidth(r, l, prop)
 		}
 
 		length += l
@@ -309,7 +530,7 @@ func FirstGraphemeClusterInString(str string, state int) (cluster, rest string,
 		} else {
 			prop = state >> shiftGraphemePropState
 		}
-		return str, "", runeWidth(r, prop), grAny | (prop << shiftGraphemePropState)
+		return str, "", runeWidth(r, length, prop), grAny | (prop << shiftGraphemePropState)
 	}
 
 	// If we don't know the state, determine it now.
@@ -319,7 +540,7 @@ func FirstGraphemeClusterInString(str string, state int) (cluster, rest string,
 	} else {
 		firstProp = state >> shiftGraphemePropState
 	}
-	width += runeWidth(r, firstProp)
+	width += runeWidth(r, length, firstProp)
 
 	// Transition until we find a boundary.
 	for {
@@ -343,7 +564,7 @@ func FirstGraphemeClusterInString(str string, state int) (cluster, rest string,
 				width = 2
 			}
 		} else if firstProp != prRegionalIndicator && firstProp != prL {
-			width += runeWidth(r, prop)
+			width += runeWidth(r, l, prop)
 		}
 
 		length += l
@@ -352,3 +573,77 @@ func FirstGraphemeClusterInString(str string, state int) (cluster, rest string,
 		}
 	}
 }
+
+// CutGraphemes slices s around the first instance of sep, returning the text
+// before and after sep. The found result reports whether sep appears in s. If
+// sep does not appear in s, CutGraphemes returns s, "", false.
+//
+// Unlike [strings.Cut], a match is only accepted if it begins and ends on a
+// grapheme cluster boundary of s. A separator that happens to occur inside a
+// grapheme cluster, for example the "e" in "e\u0301" (an "e" followed by a
+// combining acute accent), is therefore not matched.
+func CutGraphemes(s, sep string) (before, after string, found bool) {
+	if i := IndexGraphemes(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// IndexGraphemes returns the byte index of the first instance of needle in
+// haystack which begins and ends on a grapheme cluster boundary of haystack,
+// or -1 if there is no such instance. An empty needle matches at index 0.
+//
+// Unlike [strings.Index], IndexGraphemes does not match a needle which covers
+// only part of a grapheme cluster. For example, "e" is not found in "e\u0301"
+// (an "e" followed by a combining acute accent), and "\U0001F468" (man) is
+// not found in "\U0001F468\u200D\U0001F469" (a ZWJ sequence).
+func IndexGraphemes(haystack, needle string) int {
+	if len(needle) == 0 {
+		return 0
+	}
+	var (
+		offset int
+		state  = -1
+		str    = haystack
+	)
+	for len(str) >= len(needle) {
+		if strings.HasPrefix(str, needle) {
+			// Make sure the match also ends on a cluster boundary.
+			var (
+				cluster string
+				length  int
+			)
+			rest, restState := str, state
+			for length < len(needle) {
+				cluster, rest, _, restState = FirstGraphemeClusterInString(rest, restState)
+				length += len(cluster)
+			}
+			if length == len(needle) {
+				return offset
+			}
+		}
+		var cluster string
+		cluster, str, _, state = FirstGraphemeClusterInString(str, state)
+		offset += len(cluster)
+	}
+	return -1
+}
+
+// GraphemeBoundary determines whether there is a grapheme cluster boundary
+// before the rune "r", given the state returned by the previous call. It lets
+// you detect grapheme clusters in a stream of runes without encoding them in
+// UTF-8 first.
+//
+// For the first rune of the text, pass a state of -1. A boundary is always
+// reported before the first rune. For consecutive runes, pass the state
+// returned by the previous call. The end of the text is always a boundary.
+//
+// The state used by this function is not compatible with the states used by
+// [Step] or [FirstGraphemeCluster] and vice versa.
+func GraphemeBoundary(state int, r rune) (boundary bool, newState int) {
+	if state < 0 {
+		state = -1
+	}
+	newState, _, boundary = transitionGraphemeState(state, r)
+	return boundary || state < 0, newState
+}
@@ -1,6 +1,7 @@
 package runeseg
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -8,8 +9,31 @@ const benchmarkStr = "This is 🏳️‍🌈, a test string ツ for grapheme clu
 
 var benchmarkBytes = []byte(benchmarkStr)
 
+// benchmarkMultilingual is a realistic sample of multilingual text, e.g. from
+// a chat application, with Latin, Cyrillic, Greek, Arabic, Devanagari, Thai,
+// CJK, and Hangul text, combining marks, emoji, and line breaks.
+const benchmarkMultilingual = "Grüße aus Zürich! Привет, как дела? Καλημέρα κόσμε.\n" +
+	"مرحبا بالعالم. नमस्ते दुनिया। สวัสดีชาวโลก\n" +
+	"日本語のテキスト、「引用」と句読点。中文文本，包括标点符号。한국어 텍스트입니다.\n" +
+	"Thanks 👍🏽 see you at the café ☕️ — 🇩🇪🇫🇷 👨‍👩‍👧‍👦 🏳️‍🌈\n"
+
+// benchmarkCorpora are the texts used by benchmarks which compare the
+// performance for different scripts.
+var benchmarkCorpora = []struct {
+	name, text string
+}{
+	{"ASCII", strings.Repeat("The quick brown fox jumps over the lazy dog. ", 10)},
+	{"CJK", strings.Repeat("日本語のテキスト、「引用」と句読点。中文文本，包括标点符号。", 5)},
+	{"Emoji", strings.Repeat("👍🏽🙂 🇩🇪🇫🇷 👨‍👩‍👧‍👦 🏳️‍🌈 ☕️ 1️⃣ ", 5)},
+	{"Mixed", benchmarkMultilingual},
+}
+
 // Variables to avoid compiler optimizations.
-var resultRunes []rune
+var (
+	resultRunes   []rune
+	resultCount   int
+	resultOffsets []int
+)
 
 type testCase = struct {
 	original string
@@ -41,6 +65,68 @@ var testCases = []testCase{
 	{original: "\t🏳️‍🌈", expected: [][]rune{{0x9}, {0x1f3f3, 0xfe0f, 0x200d, 0x1f308}}},
 	{original: "\t🏳️‍🌈\t", expected: [][]rune{{0x9}, {0x1f3f3, 0xfe0f, 0x200d, 0x1f308}, {0x9}}},
 	{original: "\r\n\uFE0E", expected: [][]rune{{13, 10}, {0xfe0e}}},
+
+	// Thai and Lao above and below vowels and tone marks (Extend) as well as
+	// SARA AM (SpacingMark) attach to their base consonant (GB9, GB9a).
+	// Preposed vowels such as U+0E40 are not Prepend and form their own
+	// clusters.
+	{original: "\u0e01\u0e48\u0e2d\u0e19", expected: [][]rune{{0xe01, 0xe48}, {0xe2d}, {0xe19}}},                                          // "before": tone mark.
+	{original: "\u0e17\u0e35\u0e48", expected: [][]rune{{0xe17, 0xe35, 0xe48}}},                                                           // Above vowel and tone mark.
+	{original: "\u0e19\u0e49\u0e33", expected: [][]rune{{0xe19, 0xe49, 0xe33}}},                                                           // Tone mark and SARA AM.
+	{original: "\u0e1c\u0e39\u0e49\u0e43\u0e2b\u0e0d\u0e48", expected: [][]rune{{0xe1c, 0xe39, 0xe49}, {0xe43}, {0xe2b}, {0xe0d, 0xe48}}}, // Below vowel and tone mark.
+	{original: "\u0e40\u0e01\u0e35\u0e4a\u0e22\u0e27", expected: [][]rune{{0xe40}, {0xe01, 0xe35, 0xe4a}, {0xe22}, {0xe27}}},              // Preposed vowel.
+	{original: "\u0e2a\u0e27\u0e31\u0e2a\u0e14\u0e35", expected: [][]rune{{0xe2a}, {0xe27, 0xe31}, {0xe2a}, {0xe14, 0xe35}}},              // "hello".
+	{original: "\u0e81\u0eb4\u0ec8\u0e99", expected: [][]rune{{0xe81, 0xeb4, 0xec8}, {0xe99}}},                                            // Lao.
+	{original: "\u0eab\u0ebc\u0ecd\u0ec8", expected: [][]rune{{0xeab, 0xebc, 0xecd, 0xec8}}},                                              // Lao, stacked marks.
+
+	// Myanmar medial consonants attach to the preceding consonant and kinzi (NGA,
+	// ASAT, VIRAMA) joins the following consonant (GB9c). Vowel signs such as AA
+	// (U+102C) are not SpacingMark and start a new cluster.
+	{original: "\u1019\u103c\u1014\u103a\u1019\u102c", expected: [][]rune{{0x1019, 0x103c}, {0x1014, 0x103a}, {0x1019}, {0x102c}}},                                                                       // "Myanmar": medial RA.
+	{original: "\u1000\u103b\u103d\u1014\u103a\u102f\u1015\u103a", expected: [][]rune{{0x1000, 0x103b, 0x103d}, {0x1014, 0x103a, 0x102f}, {0x1015, 0x103a}}},                                             // "I": medials YA and WA.
+	{original: "\u1021\u1004\u103a\u1039\u1002\u101c\u102d\u1015\u103a", expected: [][]rune{{0x1021}, {0x1004, 0x103a, 0x1039, 0x1002}, {0x101c, 0x102d}, {0x1015, 0x103a}}},                             // "English": kinzi.
+	{original: "\u101e\u1004\u103a\u1039\u1001\u103b\u102d\u102f\u1004\u103a\u1038", expected: [][]rune{{0x101e}, {0x1004, 0x103a, 0x1039, 0x1001, 0x103b, 0x102d, 0x102f}, {0x1004, 0x103a}, {0x1038}}}, // "Cemetery": kinzi and medial.
+	{original: "\u1019\u1039\u1018\u102c", expected: [][]rune{{0x1019, 0x1039, 0x1018}, {0x102c}}},                                                                                                       // Stacked consonant.
+
+	// Prepend characters attach to the following character (GB9b) but not to
+	// controls (GB5).
+	{original: "\u0600\u0661\u0662", expected: [][]rune{{0x600, 0x661}, {0x662}}}, // ARABIC NUMBER SIGN.
+	{original: "\u0600\u0600\u0661", expected: [][]rune{{0x600, 0x600, 0x661}}},   // Multiple Prepend characters.
+	{original: "\U000110BD\U000110A6", expected: [][]rune{{0x110bd, 0x110a6}}},    // KAITHI NUMBER SIGN.
+	{original: "\u0d4e\u0d15", expected: [][]rune{{0xd4e, 0xd15}}},                // MALAYALAM LETTER DOT REPH.
+	{original: "\u0600\u0301", expected: [][]rune{{0x600, 0x301}}},                // Followed by a combining mark.
+	{original: "\u0600 ", expected: [][]rune{{0x600, 0x20}}},                      // Followed by a space.
+	{original: "a\u0600", expected: [][]rune{{0x61}, {0x600}}},                    // At the end of the text.
+	{original: "\u0600", expected: [][]rune{{0x600}}},                             // Alone.
+	{original: "\u0600\n\u0661", expected: [][]rune{{0x600}, {0xa}, {0x661}}},     // GB5: Break before controls.
+	{original: "\u0600\r\n", expected: [][]rune{{0x600}, {0xd, 0xa}}},             // GB5: Break before CR.
+	{original: "\u0e40\u0e01", expected: [][]rune{{0xe40}, {0xe01}}},              // SARA E is not Prepend.
+
+	// Conjoining jamo form one cluster per Hangul syllable (GB6-GB8), whether they
+	// are decomposed into leading (L), vowel (V), and trailing (T) jamo or partly
+	// precomposed into LV and LVT syllables.
+	{original: "\u1112\u1161", expected: [][]rune{{0x1112, 0x1161}}},               // L V.
+	{original: "\u1112\u1161\u11ab", expected: [][]rune{{0x1112, 0x1161, 0x11ab}}}, // L V T.
+	{original: "\ud558\u11ab", expected: [][]rune{{0xd558, 0x11ab}}},               // LV T.
+	{original: "\ud558\u1161", expected: [][]rune{{0xd558, 0x1161}}},               // LV V.
+	{original: "\ud55c", expected: [][]rune{{0xd55c}}},                             // LVT.
+	{original: "\ud55c\u11ab", expected: [][]rune{{0xd55c, 0x11ab}}},               // LVT T.
+	{original: "\ud55c\u1161", expected: [][]rune{{0xd55c}, {0x1161}}},             // LVT ÷ V.
+	{original: "\u1100\u1100\u1161", expected: [][]rune{{0x1100, 0x1100, 0x1161}}}, // L L V.
+	{original: "\u1100\uac00", expected: [][]rune{{0x1100, 0xac00}}},               // L LV.
+	{original: "\u1161\u11ab", expected: [][]rune{{0x1161, 0x11ab}}},               // V T.
+	{original: "\u11ab", expected: [][]rune{{0x11ab}}},                             // Standalone T.
+	{original: "a\u11ab", expected: [][]rune{{0x61}, {0x11ab}}},                    // T after a non-Hangul letter.
+	{original: "\u11ab\u1161", expected: [][]rune{{0x11ab}, {0x1161}}},             // T ÷ V.
+	{original: "\ud55c\uae00", expected: [][]rune{{0xd55c}, {0xae00}}},             // Two LVT syllables.
+	{original: "\u1112\u1161\u11ab\u1100\u1173\u11af", expected: [][]rune{{0x1112, 0x1161, 0x11ab}, {0x1100, 0x1173, 0x11af}}},
+
+	// The combining grapheme joiner U+034F, despite its name, does not join
+	// grapheme clusters. It is Extend and attaches to the preceding cluster.
+	{original: "a\u034fb", expected: [][]rune{{0x61, 0x34f}, {0x62}}},
+	{original: "a\u0308\u034f\u0323b", expected: [][]rune{{0x61, 0x308, 0x34f, 0x323}, {0x62}}},
+	{original: "\u034fa", expected: [][]rune{{0x34f}, {0x61}}},
+	{original: "\u4e16\u034f\u754c", expected: [][]rune{{0x4e16, 0x34f}, {0x754c}}},
 }
 
 // decomposed returns a grapheme cluster decomposition.
@@ -179,6 +265,49 @@ func TestGraphemesClassWord(t *testing.T) {
 	}
 }
 
+// Test that IsWordStart and IsSentenceStart report the boundaries before the
+// current grapheme cluster, i.e. the boundaries reported by IsWordBoundary and
+// IsSentenceBoundary for the previous cluster.
+func TestGraphemesWordSentenceStart(t *testing.T) {
+	for _, original := range []string{
+		"Hello, world! This is a test.",
+		"a\u0301b c\r\nd. E",
+		"\U0001F1E9\U0001F1EA\U0001F1EB\U0001F1F7 \u4e16\u754c",
+	} {
+		gr := NewGraphemes(original)
+		if gr.IsWordStart() || gr.IsSentenceStart() {
+			t.Errorf("%q: Expected no word or sentence start before Next", original)
+		}
+		word, sentence := true, true
+		wordStarts := make(map[int]bool)
+		for gr.Next() {
+			if gr.IsWordStart() != word || gr.IsSentenceStart() != sentence {
+				from, _ := gr.Positions()
+				t.Errorf("%q: cluster %q at %d has word start %t and sentence start %t, expected %t and %t",
+					original, gr.Str(), from, gr.IsWordStart(), gr.IsSentenceStart(), word, sentence)
+			}
+			from, _ := gr.Positions()
+			wordStarts[from] = word
+			word, sentence = gr.IsWordBoundary(), gr.IsSentenceBoundary()
+		}
+		if gr.IsWordStart() || gr.IsSentenceStart() {
+			t.Errorf("%q: Expected no word or sentence start past the end", original)
+		}
+
+		// The boundaries before the current cluster are also known after
+		// seeking.
+		for offset, expected := range wordStarts {
+			if !gr.Seek(offset) || !gr.Next() {
+				t.Errorf("%q: Seek to %d failed", original, offset)
+				continue
+			}
+			if gr.IsWordStart() != expected {
+				t.Errorf("%q: word start after seeking to %d is %t, expected %t", original, offset, gr.IsWordStart(), expected)
+			}
+		}
+	}
+}
+
 // Run the standard Unicode test cases for sentence boundaries using the
 // Graphemes class.
 func TestGraphemesClassSentence(t *testing.T) {
@@ -294,6 +423,140 @@ func TestGraphemesReset(t *testing.T) {
 	}
 }
 
+// Test that Reset() discards all state such that a second pass over the same
+// string yields identical results.
+func TestGraphemesResetFullState(t *testing.T) {
+	type result struct {
+		cluster          string
+		word, sentence   bool
+		lineBreak, width int
+		fromPos, toPos   int
+	}
+	iterate := func(gr *Graphemes) (results []result) {
+		for gr.Next() {
+			from, to := gr.Positions()
+			results = append(results, result{
+				cluster:   gr.Str(),
+				word:      gr.IsWordBoundary(),
+				sentence:  gr.IsSentenceBoundary(),
+				lineBreak: gr.LineBreak(),
+				width:     gr.Width(),
+				fromPos:   from,
+				toPos:     to,
+			})
+		}
+		return
+	}
+
+	gr := NewGraphemes("Hi 🏳️‍🌈! 世界。🇩🇪 Ok.\nBye")
+	first := iterate(gr)
+	gr.Reset()
+	if !gr.IsWordBoundary() || !gr.IsSentenceBoundary() || gr.LineBreak() != LineDontBreak || gr.Width() != 0 {
+		t.Errorf("Reset iterator reports leftover boundary information: %d", gr.boundaries)
+	}
+	if from, to := gr.Positions(); from != 0 || to != 0 {
+		t.Errorf(`Expected from=%d to=%d after reset, got from=%d to=%d`, 0, 0, from, to)
+	}
+	second := iterate(gr)
+	if len(first) != len(second) {
+		t.Fatalf("Second pass returned %d clusters, first pass returned %d", len(second), len(first))
+	}
+	for index := range first {
+		if first[index] != second[index] {
+			t.Errorf("Cluster %d differs after reset: %+v, expected %+v", index, second[index], first[index])
+		}
+	}
+
+	// Resetting in the middle of the string must behave the same way.
+	gr.Reset()
+	gr.Next()
+	gr.Next()
+	gr.Reset()
+	third := iterate(gr)
+	for index := range first {
+		if index >= len(third) || first[index] != third[index] {
+			t.Fatalf("Cluster %d differs after mid-string reset", index)
+		}
+	}
+}
+
+// Test that seeking and then iterating yields the same clusters and boundary
+// information as iterating over the entire string.
+func TestGraphemesSeek(t *testing.T) {
+	type result struct {
+		cluster        string
+		boundaries     int
+		fromPos, toPos int
+	}
+	iterate := func(gr *Graphemes) (results []result) {
+		for gr.Next() {
+			from, to := gr.Positions()
+			results = append(results, result{
+				cluster:    gr.Str(),
+				boundaries: gr.BoundaryFlags(),
+				fromPos:    from,
+				toPos:      to,
+			})
+		}
+		return
+	}
+
+	str := "Hi \U0001F3F3\ufe0f\u200d\U0001F308! \u4e16\u754c\u3002\U0001F1E9\U0001F1EA Ok.\nBye"
+	all := iterate(NewGraphemes(str))
+	check := func(gr *Graphemes, offset int) {
+		t.Helper()
+		expected := all
+		for len(expected) > 0 && expected[0].fromPos < offset {
+			expected = expected[1:]
+		}
+		results := iterate(gr)
+		if len(results) != len(expected) {
+			t.Fatalf("Seek(%d): got %d clusters, expected %d", offset, len(results), len(expected))
+		}
+		for index := range results {
+			if results[index] != expected[index] {
+				t.Errorf("Seek(%d): cluster %d is %+v, expected %+v", offset, index, results[index], expected[index])
+			}
+		}
+	}
+
+	// Seek from a new iterator, forward from the middle, and backward.
+	gr := NewGraphemes(str)
+	for offset := 0; offset <= len(str); offset++ {
+		gr.Reset()
+		if !gr.Seek(offset) {
+			t.Fatalf("Seek(%d) failed", offset)
+		}
+		check(gr, offset)
+		for _, from := range []int{offset / 2, len(str) - 1, len(str)} {
+			gr.Reset()
+			gr.Seek(from)
+			if !gr.Seek(offset) {
+				t.Fatalf("Seek(%d) after Seek(%d) failed", offset, from)
+			}
+			check(gr, offset)
+		}
+	}
+
+	// Seeking after the end of the iteration.
+	gr.Reset()
+	iterate(gr)
+	gr.Seek(3)
+	check(gr, 3)
+
+	// Invalid offsets.
+	gr.Reset()
+	gr.Next()
+	for _, offset := range []int{-1, len(str) + 1} {
+		if gr.Seek(offset) {
+			t.Errorf("Seek(%d) succeeded, expected failure", offset)
+		}
+	}
+	if gr.Str() != "H" {
+		t.Errorf("Failed Seek changed the iterator, current cluster is %q", gr.Str())
+	}
+}
+
 // Test retrieving clusters before calling Next().
 func TestGraphemesEarly(t *testing.T) {
 	gr := NewGraphemes("test")
@@ -345,6 +608,345 @@ func TestGraphemesCount(t *testing.T) {
 	}
 }
 
+// Test the ClusterReport and CodePointCount functions.
+func TestClusterReport(t *testing.T) {
+	for _, testCase := range []struct {
+		original                           string
+		bytes, codePoints, clusters, width int
+	}{
+		{"", 0, 0, 0, 0},
+		{"abc", 3, 3, 3, 3},
+		{"\U0001F468\u200d\U0001F469\u200d\U0001F467\u200d\U0001F466", 25, 7, 1, 2}, // Family emoji.
+		{"e\u0301", 3, 2, 1, 1}, // Decomposed.
+		{"\u00e9", 2, 1, 1, 1},  // Precomposed.
+		{"\u4e16\u754c", 6, 2, 2, 4},
+		{"\U0001F1E9\U0001F1EA\U0001F1EB", 12, 3, 2, 4},
+		{"a\xffb", 3, 3, 3, 3}, // Invalid UTF-8.
+		{"\r\n", 2, 2, 1, 0},
+	} {
+		bytes, codePoints, clusters, width := ClusterReport(testCase.original)
+		if bytes != testCase.bytes || codePoints != testCase.codePoints || clusters != testCase.clusters || width != testCase.width {
+			t.Errorf("%q: got %d bytes, %d code points, %d clusters, width %d, expected %d, %d, %d, %d", testCase.original, bytes, codePoints, clusters, width, testCase.bytes, testCase.codePoints, testCase.clusters, testCase.width)
+		}
+		if n := CodePointCount(testCase.original); n != testCase.codePoints {
+			t.Errorf("%q: CodePointCount returned %d, expected %d", testCase.original, n, testCase.codePoints)
+		}
+		if n := GraphemeClusterCount(testCase.original); n != clusters {
+			t.Errorf("%q: GraphemeClusterCount returned %d, ClusterReport %d", testCase.original, n, clusters)
+		}
+		if w := StringWidth(testCase.original); w != width {
+			t.Errorf("%q: StringWidth returned %d, ClusterReport %d", testCase.original, w, width)
+		}
+	}
+}
+
+// Test the conversion between grapheme cluster indices and rune indices.
+func TestGraphemeRuneIndex(t *testing.T) {
+	const family = "\U0001F468\u200d\U0001F469\u200d\U0001F467\u200d\U0001F466"
+	for _, testCase := range []struct {
+		original      string
+		graphemeIndex int
+		runeIndex     int
+	}{
+		{family + "x", 0, 0},
+		{family + "x", 1, 7},
+		{family + "x", 2, 8},
+		{"e\u0301\u00e9x", 1, 2},
+		{"e\u0301\u00e9x", 2, 3},
+		{"a\r\nb", 2, 3},
+		{"", 0, 0},
+	} {
+		if runeIndex := GraphemeToRuneIndex(testCase.original, testCase.graphemeIndex); runeIndex != testCase.runeIndex {
+			t.Errorf("GraphemeToRuneIndex(%q, %d) = %d, expected %d", testCase.original, testCase.graphemeIndex, runeIndex, testCase.runeIndex)
+		}
+		if graphemeIndex := RuneToGraphemeIndex(testCase.original, testCase.runeIndex); graphemeIndex != testCase.graphemeIndex {
+			t.Errorf("RuneToGraphemeIndex(%q, %d) = %d, expected %d", testCase.original, testCase.runeIndex, graphemeIndex, testCase.graphemeIndex)
+		}
+	}
+
+	// Rune indices inside a cluster snap to its start.
+	for runeIndex := 0; runeIndex < 7; runeIndex++ {
+		if graphemeIndex := RuneToGraphemeIndex(family+"x", runeIndex); graphemeIndex != 0 {
+			t.Errorf("RuneToGraphemeIndex(%q, %d) = %d, expected 0", family+"x", runeIndex, graphemeIndex)
+		}
+	}
+
+	// Out-of-range indices.
+	if runeIndex := GraphemeToRuneIndex("abc", -1); runeIndex != 0 {
+		t.Errorf("GraphemeToRuneIndex with a negative index returned %d, expected 0", runeIndex)
+	}
+	if runeIndex := GraphemeToRuneIndex(family+"x", 5); runeIndex != 8 {
+		t.Errorf("GraphemeToRuneIndex beyond the end returned %d, expected 8", runeIndex)
+	}
+	if graphemeIndex := RuneToGraphemeIndex("abc", -1); graphemeIndex != 0 {
+		t.Errorf("RuneToGraphemeIndex with a negative index returned %d, expected 0", graphemeIndex)
+	}
+	if graphemeIndex := RuneToGraphemeIndex(family+"x", 20); graphemeIndex != 2 {
+		t.Errorf("RuneToGraphemeIndex beyond the end returned %d, expected 2", graphemeIndex)
+	}
+
+	// Round trip at every cluster.
+	for _, testCase := range testCases {
+		for graphemeIndex := 0; graphemeIndex <= len(testCase.expected); graphemeIndex++ {
+			runeIndex := GraphemeToRuneIndex(testCase.original, graphemeIndex)
+			if back := RuneToGraphemeIndex(testCase.original, runeIndex); back != graphemeIndex {
+				t.Errorf("%q: grapheme index %d maps to rune index %d which maps back to %d", testCase.original, graphemeIndex, runeIndex, back)
+			}
+		}
+	}
+}
+
+// Test the AllGraphemes function and the Graphemes.All method.
+func TestAllGraphemes(t *testing.T) {
+	for testNum, testCase := range graphemeBreakTestCases {
+		g := NewGraphemes(testCase.original)
+		g.Next()
+		for _, clusters := range [][]string{AllGraphemes(testCase.original), g.All()} {
+			if len(clusters) != len(testCase.expected) {
+				t.Errorf("Test case %d %q: %d clusters, expected %d", testNum, testCase.original, len(clusters), len(testCase.expected))
+				continue
+			}
+			for i, cluster := range clusters {
+				if cluster != string(testCase.expected[i]) {
+					t.Errorf("Test case %d %q: cluster %d is %q, expected %q", testNum, testCase.original, i, cluster, string(testCase.expected[i]))
+				}
+			}
+		}
+		if from, _ := g.Positions(); from != 0 {
+			t.Errorf("Test case %d %q: All changed the iterator's position", testNum, testCase.original)
+		}
+	}
+	if clusters := AllGraphemes(""); clusters != nil {
+		t.Errorf("Expected no clusters, got %q", clusters)
+	}
+}
+
+// Test the GraphemeBoundaries function.
+func TestGraphemeBoundaries(t *testing.T) {
+	for _, original := range []string{
+		"",
+		"abc",
+		"\U0001F468\u200d\U0001F469\u200d\U0001F467\u200d\U0001F466 \U0001F44D\U0001F3FD\U0001F1E9\U0001F1EA\U0001F1EB\U0001F1F7\U0001F3F3\ufe0f\u200d\U0001F308!",
+		"\u2764\ufe0f\U0001F525 1\ufe0f\u20e3 \U0001F469\U0001F3FB\u200d\U0001F4BB\r\n\U0001F1FA",
+		benchmarkStr,
+	} {
+		expected := []int{0}
+		var offset int
+		for str, state := original, -1; len(str) > 0; {
+			var cluster string
+			cluster, str, _, state = FirstGraphemeClusterInString(str, state)
+			offset += len(cluster)
+			expected = append(expected, offset)
+		}
+		if boundaries := GraphemeBoundaries(original); !equalOffsets(boundaries, expected) {
+			t.Errorf("%q: got boundaries %v, expected %v", original, boundaries, expected)
+		}
+	}
+	for testNum, testCase := range graphemeBreakTestCases {
+		expected := append([]int{0}, segmentEnds(testCase.expected)...)
+		if boundaries := GraphemeBoundaries(testCase.original); !equalOffsets(boundaries, expected) {
+			t.Errorf("Test case %d %q: got boundaries %v, expected %v", testNum, testCase.original, boundaries, expected)
+		}
+	}
+}
+
+// Test that Graphemes.BoundaryFlags returns the same values as StepString and
+// the individual boundary methods.
+func TestGraphemesBoundaryFlags(t *testing.T) {
+	for _, testCase := range testCases {
+		g := NewGraphemes(testCase.original)
+		if flags := g.BoundaryFlags(); flags != MaskWord|MaskSentence|LineDontBreak {
+			t.Errorf("%q: unexpected flags %x before the first cluster", testCase.original, flags)
+		}
+		str, state := testCase.original, -1
+		for g.Next() {
+			var (
+				cluster    string
+				boundaries int
+			)
+			cluster, str, boundaries, state = StepString(str, state)
+			flags := g.BoundaryFlags()
+			if cluster != g.Str() || flags != boundaries {
+				t.Errorf("%q: cluster %q has flags %x, StepString returned %q with %x", testCase.original, g.Str(), flags, cluster, boundaries)
+			}
+			if (flags&MaskWord != 0) != g.IsWordBoundary() ||
+				(flags&MaskSentence != 0) != g.IsSentenceBoundary() ||
+				flags&MaskLine != g.LineBreak() ||
+				flags>>ShiftWidth != g.Width() {
+				t.Errorf("%q: flags %x of cluster %q differ from the individual methods", testCase.original, flags, g.Str())
+			}
+		}
+		if flags := g.BoundaryFlags(); flags != MaskWord|MaskSentence|LineMustBreak {
+			t.Errorf("%q: unexpected flags %x after the last cluster", testCase.original, flags)
+		}
+	}
+}
+
+// Test that the widths of the consumed clusters and the remaining width always
+// add up to the width of the entire string.
+func TestGraphemesRemainingWidth(t *testing.T) {
+	for _, testCase := range testCases {
+		total := StringWidth(testCase.original)
+		g := NewGraphemes(testCase.original)
+		if w := g.RemainingWidth(); w != total {
+			t.Errorf("%q: remaining width %d before iterating, expected %d", testCase.original, w, total)
+		}
+		var consumed int
+		for g.Next() {
+			consumed += g.Width()
+			if w := g.RemainingWidth(); consumed+w != total {
+				t.Errorf("%q: consumed width %d and remaining width %d after %q, expected a total of %d", testCase.original, consumed, w, g.Str(), total)
+			}
+		}
+		if w := g.RemainingWidth(); w != 0 {
+			t.Errorf("%q: remaining width %d after iterating, expected 0", testCase.original, w)
+		}
+	}
+}
+
+// Test the GraphemeClusterCountRunes function against GraphemeClusterCount.
+func TestGraphemesCountRunes(t *testing.T) {
+	if n := GraphemeClusterCountRunes(nil); n != 0 {
+		t.Errorf(`Expected 0 grapheme clusters, got %d`, n)
+	}
+	for testNum, testCase := range graphemeBreakTestCases {
+		if n, expected := GraphemeClusterCountRunes([]rune(testCase.original)), GraphemeClusterCount(testCase.original); n != expected {
+			t.Errorf("Test case %d %q: GraphemeClusterCountRunes returned %d, expected %d", testNum, testCase.original, n, expected)
+		}
+	}
+}
+
+// Test Arabic text. The harakat (U+064B..U+0652) are combining marks which
+// attach to the preceding letter and have no width. The letters are class AL,
+// so lines are only broken after spaces.
+func TestGraphemesArabic(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		clusters []string
+		lines    []string
+	}{
+		{
+			"\u0627\u0644\u0633\u0644\u0627\u0645", // "Peace", without harakat.
+			[]string{"\u0627", "\u0644", "\u0633", "\u0644", "\u0627", "\u0645"},
+			[]string{"\u0627\u0644\u0633\u0644\u0627\u0645"},
+		},
+		{
+			"\u0627\u0644\u0633\u064e\u0651\u0644\u064e\u0627\u0645\u064f \u0639\u064e\u0644\u064e\u064a\u0652\u0643\u064f\u0645\u0652", // "Peace be upon you", with harakat.
+			[]string{"\u0627", "\u0644", "\u0633\u064e\u0651", "\u0644\u064e", "\u0627", "\u0645\u064f", " ", "\u0639\u064e", "\u0644\u064e", "\u064a\u0652", "\u0643\u064f", "\u0645\u0652"},
+			[]string{"\u0627\u0644\u0633\u064e\u0651\u0644\u064e\u0627\u0645\u064f ", "\u0639\u064e\u0644\u064e\u064a\u0652\u0643\u064f\u0645\u0652"},
+		},
+		{
+			"\u0643\u0650\u062a\u064e\u0627\u0628\u064c", // "Book", with tanwin.
+			[]string{"\u0643\u0650", "\u062a\u064e", "\u0627", "\u0628\u064c"},
+			[]string{"\u0643\u0650\u062a\u064e\u0627\u0628\u064c"},
+		},
+		{
+			"\u0645\u0631\u062d\u0628\u0627\u060c \u0643\u064a\u0641\u061f", // "Hello, how?", with Arabic punctuation.
+			[]string{"\u0645", "\u0631", "\u062d", "\u0628", "\u0627", "\u060c", " ", "\u0643", "\u064a", "\u0641", "\u061f"},
+			[]string{"\u0645\u0631\u062d\u0628\u0627\u060c ", "\u0643\u064a\u0641\u061f"},
+		},
+	} {
+		var clusters []string
+		g := NewGraphemes(testCase.original)
+		for g.Next() {
+			clusters = append(clusters, g.Str())
+			if g.Width() != 1 {
+				t.Errorf("%q: cluster %q has width %d, expected 1", testCase.original, g.Str(), g.Width())
+			}
+		}
+		if strings.Join(clusters, "|") != strings.Join(testCase.clusters, "|") {
+			t.Errorf("%q: got clusters %q, expected %q", testCase.original, clusters, testCase.clusters)
+		}
+		if n := GraphemeClusterCount(testCase.original); n != len(testCase.clusters) {
+			t.Errorf("%q: GraphemeClusterCount returned %d, expected %d", testCase.original, n, len(testCase.clusters))
+		}
+		if w := StringWidth(testCase.original); w != len(testCase.clusters) {
+			t.Errorf("%q: StringWidth returned %d, expected %d", testCase.original, w, len(testCase.clusters))
+		}
+		lines := lineSegmentsOf(testCase.original)
+		if strings.Join(lines, "|") != strings.Join(testCase.lines, "|") {
+			t.Errorf("%q: got line segments %q, expected %q", testCase.original, lines, testCase.lines)
+		}
+	}
+	for r := rune(0x064b); r <= 0x0652; r++ {
+		if prop := propertyGraphemes(r); prop != prExtend {
+			t.Errorf("Grapheme property of %U is %d, expected Extend", r, prop)
+		}
+		if w := StringWidth("\u0628" + string(r)); w != 1 {
+			t.Errorf("%U adds width %d, expected 0", r, w-1)
+		}
+	}
+}
+
+// Test the width and line breaks of text with the combining grapheme joiner
+// U+034F. Its grapheme clusters are tested with the testCases table.
+func TestGraphemesCombiningGraphemeJoiner(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		width    int
+	}{
+		{"a\u034fb", 2},
+		{"a\u0308\u034f\u0323b", 2},
+		{"\u034fa", 1},
+		{"\u4e16\u034f\u754c", 4},
+	} {
+		var (
+			lineBreaks []int
+			width      int
+		)
+		str, state := testCase.original, -1
+		for len(str) > 0 {
+			var boundaries int
+			_, str, boundaries, state = StepString(str, state)
+			width += boundaries >> ShiftWidth
+			lineBreaks = append(lineBreaks, boundaries&MaskLine)
+		}
+		if width != testCase.width {
+			t.Errorf("%q: got width %d, expected %d", testCase.original, width, testCase.width)
+		}
+		if w := StringWidth(testCase.original); w != testCase.width {
+			t.Errorf("%q: StringWidth returned %d, expected %d", testCase.original, w, testCase.width)
+		}
+
+		// The line breaks must be the same as without the joiner.
+		if strings.HasPrefix(testCase.original, "\u034f") {
+			continue
+		}
+		str, state = strings.ReplaceAll(testCase.original, "\u034f", ""), -1
+		for index := 0; len(str) > 0; index++ {
+			var boundaries int
+			_, str, boundaries, state = StepString(str, state)
+			if index >= len(lineBreaks) || boundaries&MaskLine != lineBreaks[index] {
+				t.Errorf("%q: got line breaks %v, different from those without U+034F", testCase.original, lineBreaks)
+				break
+			}
+		}
+	}
+	if w := StringWidth("\u034f"); w != 0 {
+		t.Errorf("U+034F has width %d, expected 0", w)
+	}
+}
+
+// Test malformed emoji ZWJ sequences. A second ZWJ attaches to the cluster
+// (GB9) but GB11 only joins a pictograph following a single ZWJ, as Extend*
+// does not include ZWJ. The next pictograph starts a new cluster.
+func TestGraphemesDoubleZWJ(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		clusters []string
+		widths   []int
+	}{
+		{"\U0001F44D\u200d\u200d\U0001F44D", []string{"\U0001F44D\u200d\u200d", "\U0001F44D"}, []int{2, 2}},
+		{"\U0001F44D\u200d\U0001F44D", []string{"\U0001F44D\u200d\U0001F44D"}, []int{2}},
+		{"\U0001F44D\u0308\u200d\U0001F44D", []string{"\U0001F44D\u0308\u200d\U0001F44D"}, []int{2}},
+		{"\U0001F44D\u200d\u0308\u200d\U0001F44D", []string{"\U0001F44D\u200d\u0308\u200d", "\U0001F44D"}, []int{2, 2}},
+		{"\U0001F44D\u200d\u200d", []string{"\U0001F44D\u200d\u200d"}, []int{2}},
+		{"\u200d\u200d\U0001F44D", []string{"\u200d\u200d", "\U0001F44D"}, []int{0, 2}},
+	} {
+		checkClusterWidths(t, testCase.original, testCase.clusters, testCase.widths)
+	}
+}
+
 // Test the ReverseString function.
 func TestReverseString(t *testing.T) {
 	for _, testCase := range testCases {
@@ -369,6 +971,73 @@ func TestReverseString(t *testing.T) {
 	}
 }
 
+// Test the CutGraphemes function.
+func TestCutGraphemes(t *testing.T) {
+	for _, testCase := range []struct {
+		s, sep        string
+		before, after string
+		found         bool
+	}{
+		{"a,b", ",", "a", "b", true},
+		{"a,b", ";", "a,b", "", false},
+		{"abc", "", "", "abc", true},
+		{"", "x", "", "", false},
+		{"e\u0301", "e", "e\u0301", "", false},                 // "e" is part of the cluster.
+		{"cafe\u0301 noire", "e", "cafe\u0301 noir", "", true}, // Only the second "e" matches.
+		{"a\u0301b", "a\u0301", "", "b", true},
+		{"🇩🇪🇫🇷", "🇪🇫", "🇩🇪🇫🇷", "", false}, // Spans two flags.
+		{"🇩🇪🇫🇷", "🇫🇷", "🇩🇪", "", true},
+		{"x👍\u200d", "👍", "x👍\u200d", "", false},
+	} {
+		before, after, found := CutGraphemes(testCase.s, testCase.sep)
+		if before != testCase.before || after != testCase.after || found != testCase.found {
+			t.Errorf(`CutGraphemes(%q, %q) = %q, %q, %v, expected %q, %q, %v`,
+				testCase.s, testCase.sep,
+				before, after, found,
+				testCase.before, testCase.after, testCase.found)
+		}
+	}
+
+	// A naive cut splits the cluster.
+	if before, _, _ := strings.Cut("e\u0301", "e"); before != "" {
+		t.Errorf(`Expected strings.Cut to split the cluster, got %q`, before)
+	}
+}
+
+// Test the IndexGraphemes function, including needles which strings.Index
+// finds inside grapheme clusters.
+func TestIndexGraphemes(t *testing.T) {
+	for _, testCase := range []struct {
+		haystack, needle string
+		expected         int
+		misaligned       bool // Whether strings.Index matches inside a cluster.
+	}{
+		{"abc", "b", 1, false},
+		{"abc", "", 0, false},
+		{"abc", "x", -1, false},
+		{"", "x", -1, false},
+		{"e\u0301", "e", -1, true},             // Needle ends inside the cluster.
+		{"e\u0301", "\u0301", -1, true},        // Needle starts inside the cluster.
+		{"cafe\u0301 noire", "e", 11, true},    // Only the second "e" matches.
+		{"cafe\u0301 noire", "cafe", -1, true}, // Missing the accent.
+		{"cafe\u0301 noire", "cafe\u0301", 0, false},
+		{"\U0001F468\u200d\U0001F469", "\U0001F468", -1, true}, // Part of a ZWJ sequence.
+		{"\U0001F468\u200d\U0001F469 \U0001F468", "\U0001F468", 12, true},
+		{"\U0001F1E9\U0001F1EA\U0001F1EB\U0001F1F7", "\U0001F1EA\U0001F1EB", -1, true}, // Spans two flags.
+		{"\U0001F1E9\U0001F1EA\U0001F1EB\U0001F1F7", "\U0001F1EB\U0001F1F7", 8, false},
+		{"\u1100\u1161\u11a8", "\u1100\u1161", -1, true}, // Hangul syllable from jamo.
+		{"a\r\nb", "\r", -1, true},
+		{"a\r\nb", "\r\n", 1, false},
+	} {
+		if index := IndexGraphemes(testCase.haystack, testCase.needle); index != testCase.expected {
+			t.Errorf("IndexGraphemes(%q, %q) = %d, expected %d", testCase.haystack, testCase.needle, index, testCase.expected)
+		}
+		if misaligned := strings.Index(testCase.haystack, testCase.needle) != testCase.expected; misaligned != testCase.misaligned {
+			t.Errorf("%q, %q: strings.Index misalignment is %v, expected %v", testCase.haystack, testCase.needle, misaligned, testCase.misaligned)
+		}
+	}
+}
+
 // Run all lists of test cases using the Graphemes function for byte slices.
 func TestGraphemesFunctionBytes(t *testing.T) {
 	allCases := append(testCases, graphemeBreakTestCases...)
@@ -545,3 +1214,68 @@ func BenchmarkGraphemesFunctionString(b *testing.B) {
 		}
 	}
 }
+
+// Benchmark counting grapheme clusters in a string.
+func BenchmarkGraphemeClusterCount(b *testing.B) {
+	b.Run("Default", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			resultCount = GraphemeClusterCount(benchmarkStr)
+		}
+	})
+	for _, corpus := range benchmarkCorpora {
+		b.Run(corpus.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(corpus.text)))
+			for i := 0; i < b.N; i++ {
+				resultCount = GraphemeClusterCount(corpus.text)
+			}
+		})
+	}
+}
+
+// Benchmark counting grapheme clusters in a rune slice, compared to converting
+// it to a string first.
+func BenchmarkGraphemeClusterCountRunes(b *testing.B) {
+	runes := []rune(strings.Repeat("\u4e16\u754c \u0928\u092e\u0938\u094d\u0924\u0947 \U0001F3F3\uFE0F\u200D\U0001F308 ", 20))
+	b.Run("Runes", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			resultCount = GraphemeClusterCountRunes(runes)
+		}
+	})
+	b.Run("String", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			resultCount = GraphemeClusterCount(string(runes))
+		}
+	})
+}
+
+// Test that GraphemeBoundary reproduces the clusters of the official Unicode
+// test cases when feeding it one rune at a time.
+func TestGraphemeBoundary(t *testing.T) {
+	for testNum, testCase := range graphemeBreakTestCases {
+		var (
+			clusters [][]rune
+			boundary bool
+		)
+		state := -1
+		for _, r := range testCase.original {
+			boundary, state = GraphemeBoundary(state, r)
+			if boundary {
+				clusters = append(clusters, nil)
+			}
+			clusters[len(clusters)-1] = append(clusters[len(clusters)-1], r)
+		}
+		if len(clusters) != GraphemeClusterCount(testCase.original) {
+			t.Errorf("Test case %d %q: %d clusters, GraphemeClusterCount returned %d", testNum, testCase.original, len(clusters), GraphemeClusterCount(testCase.original))
+		}
+		if len(clusters) != len(testCase.expected) {
+			t.Errorf("Test case %d %q: %d clusters, expected %d", testNum, testCase.original, len(clusters), len(testCase.expected))
+			continue
+		}
+		for i, cluster := range clusters {
+			if string(cluster) != string(testCase.expected[i]) {
+				t.Errorf("Test case %d %q: cluster %d is %x, expected %x", testNum, testCase.original, i, cluster, testCase.expected[i])
+			}
+		}
+	}
+}
@@ -1891,3 +1891,11 @@ var graphemeCodePoints = [][3]int{
 	{0xE0100, 0xE01EF, prExtend},               // Mn [240] VARIATION SELECTOR-17..VARIATION SELECTOR-256
 	{0xE01F0, 0xE0FFF, prControl},              // Cn [3600] <reserved-E01F0>..<reserved-E0FFF>
 }
+
+// graphemeCodePointsVersion is the Unicode version of the data file
+// graphemeCodePoints was generated from.
+// graphemeCodePointsDate is the date of the generation.
+const (
+	graphemeCodePointsVersion = "17.0.0"
+	graphemeCodePointsDate    = "2026-01-05"
+)
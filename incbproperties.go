@@ -513,3 +513,11 @@ var incbCodePoints = [][3]int{
 	{0xE0020, 0xE007F, prInCBExtend},    // Cf  [96] TAG SPACE..CANCEL TAG
 	{0xE0100, 0xE01EF, prInCBExtend},    // Mn [240] VARIATION SELECTOR-17..VARIATION SELECTOR-256
 }
+
+// incbCodePointsVersion is the Unicode version of the data file
+// incbCodePoints was generated from.
+// incbCodePointsDate is the date of the generation.
+const (
+	incbCodePointsVersion = "17.0.0"
+	incbCodePointsDate    = "2026-01-04"
+)
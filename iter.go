@@ -0,0 +1,95 @@
+//go:build go1.23
+
+package runeseg
+
+import "iter"
+
+// Iterate returns an iterator over the remaining grapheme clusters, starting
+// after the current one. It advances the [Graphemes] iterator, i.e. it is
+// an alternative to calling [Graphemes.Next] in a loop:
+//
+//	for cluster := range g.Iterate() {
+//		fmt.Println(cluster)
+//	}
+//
+// Inside the loop, all other methods of [Graphemes] refer to the current
+// cluster.
+func (g *Graphemes) Iterate() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for g.Next() {
+			if !yield(g.cluster) {
+				return
+			}
+		}
+	}
+}
+
+// GraphemesSeq returns an iterator over the grapheme clusters of the given
+// string. See [FirstGraphemeClusterInString] for details.
+func GraphemesSeq(s string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		var cluster string
+		str, state := s, -1
+		for len(str) > 0 {
+			cluster, str, _, state = FirstGraphemeClusterInString(str, state)
+			if !yield(cluster) {
+				return
+			}
+		}
+	}
+}
+
+// WordsSeq returns an iterator over the words of the given string, including
+// the segments between words such as spaces and punctuation. See
+// [FirstWordInString] for details.
+func WordsSeq(s string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		var word string
+		str, state := s, -1
+		for len(str) > 0 {
+			word, str, state = FirstWordInString(str, state)
+			if !yield(word) {
+				return
+			}
+		}
+	}
+}
+
+// SentencesSeq returns an iterator over the sentences of the given string. See
+// [FirstSentenceInString] for details.
+func SentencesSeq(s string) iter.Seq[string] {
+	return func(yield func(string) bool) {
+		var sentence string
+		str, state := s, -1
+		for len(str) > 0 {
+			sentence, str, state = FirstSentenceInString(str, state)
+			if !yield(sentence) {
+				return
+			}
+		}
+	}
+}
+
+// LineSegmentsSeq returns an iterator over the segments between the line break
+// opportunities of the given string. The second value is the line break after
+// the segment, either [LineCanBreak] or [LineMustBreak]. See
+// [FirstLineSegmentInString] for details.
+func LineSegmentsSeq(s string) iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		var (
+			segment   string
+			mustBreak bool
+		)
+		str, state := s, -1
+		for len(str) > 0 {
+			segment, str, mustBreak, state = FirstLineSegmentInString(str, state)
+			lineBreak := LineCanBreak
+			if mustBreak {
+				lineBreak = LineMustBreak
+			}
+			if !yield(segment, lineBreak) {
+				return
+			}
+		}
+	}
+}
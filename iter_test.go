@@ -0,0 +1,71 @@
+//go:build go1.23
+
+package runeseg
+
+import "testing"
+
+// Test that the iterators return the same segments as the First* functions and
+// that they stop when requested.
+func TestSeq(t *testing.T) {
+	for _, testCase := range wordBreakTestCases {
+		var words []string
+		for word := range WordsSeq(testCase.original) {
+			words = append(words, word)
+		}
+		if len(words) != len(testCase.expected) {
+			t.Errorf("%q: got words %q, expected %d words", testCase.original, words, len(testCase.expected))
+			continue
+		}
+		for i := range words {
+			if words[i] != string(testCase.expected[i]) {
+				t.Errorf("%q: word %d is %q, expected %q", testCase.original, i, words[i], string(testCase.expected[i]))
+			}
+		}
+	}
+	for _, testCase := range sentenceBreakTestCases {
+		var sentences []string
+		for sentence := range SentencesSeq(testCase.original) {
+			sentences = append(sentences, sentence)
+		}
+		if len(sentences) != len(testCase.expected) {
+			t.Errorf("%q: got sentences %q, expected %d sentences", testCase.original, sentences, len(testCase.expected))
+		}
+	}
+	for _, testCase := range graphemeBreakTestCases {
+		var n int
+		for range GraphemesSeq(testCase.original) {
+			n++
+		}
+		if n != len(testCase.expected) {
+			t.Errorf("%q: got %d clusters, expected %d", testCase.original, n, len(testCase.expected))
+		}
+	}
+	var segments []LineSegment
+	for segment, lineBreak := range LineSegmentsSeq("a b\nc") {
+		segments = append(segments, LineSegment{segment, lineBreak == LineMustBreak})
+	}
+	if expected := SplitLines("a b\nc"); len(segments) != len(expected) || segments[0] != expected[0] || segments[1] != expected[1] || segments[2] != expected[2] {
+		t.Errorf("Got line segments %v, expected %v", segments, expected)
+	}
+
+	// Stop early.
+	var n int
+	for range WordsSeq("one two three") {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 iterations, got %d", n)
+	}
+	g := NewGraphemes("abc")
+	for cluster := range g.Iterate() {
+		if cluster == "b" {
+			break
+		}
+	}
+	if g.Str() != "b" {
+		t.Errorf("Expected the iterator to be at %q, got %q", "b", g.Str())
+	}
+}
@@ -1,6 +1,9 @@
 package runeseg
 
-import "unicode/utf8"
+import (
+	"strings"
+	"unicode/utf8"
+)
 
 // FirstLineSegment returns the prefix of the given byte slice after which a
 // decision to break the string over to the next line can or must be made,
@@ -52,6 +55,14 @@ func FirstLineSegment(b []byte, state int) (segment, rest []byte, mustBreak bool
 		return
 	}
 
+	// Fast path for ASCII words.
+	if length, mustBreak, newState := asciiLineSegment(b, state); length >= 0 {
+		if len(b) <= length {
+			return b, nil, true, lbcAny // LB3.
+		}
+		return b[:length], b[length:], mustBreak, newState
+	}
+
 	// Extract the first rune.
 	r, length := utf8.DecodeRune(b)
 	if len(b) <= length { // If we're already past the end, there is nothing else to parse.
@@ -88,6 +99,14 @@ func FirstLineSegmentInString(str string, state int) (segment, rest string, must
 		return
 	}
 
+	// Fast path for ASCII words.
+	if length, mustBreak, newState := asciiLineSegment(str, state); length >= 0 {
+		if len(str) <= length {
+			return str, "", true, lbcAny // LB3.
+		}
+		return str[:length], str[length:], mustBreak, newState
+	}
+
 	// Extract the first rune.
 	r, length := utf8.DecodeRuneInString(str)
 	if len(str) <= length { // If we're already past the end, there is nothing else to parse.
@@ -116,6 +135,298 @@ func FirstLineSegmentInString(str string, state int) (segment, rest string, must
 	}
 }
 
+// The packed line break states (see [packLineContext]) after a break before
+// an ASCII letter or digit, used by asciiLineSegment.
+const (
+	lineStateAL = lbcAL
+	lineStateNU = lbCtxNumeric<<8 | lbcNU
+)
+
+// asciiLineSegment is a fast path for [FirstLineSegment] and
+// [FirstLineSegmentInString] covering the most common segments of English
+// text and log files: ASCII letters and digits (classes AL and NU, between
+// which there are no break opportunities), followed by spaces (LB18) or a
+// line feed (LB5), followed by another ASCII letter or digit or by the end of
+// the text. It returns the length of the segment, whether the break after it
+// is mandatory, and the new state, all identical to what the full state
+// machine would return. If the text doesn't start with such a segment or if
+// the state is not one the fast path can continue from, a negative length is
+// returned.
+func asciiLineSegment[T []byte | string](text T, state int) (length int, mustBreak bool, newState int) {
+	// The first character must match the state. The states lineStateAL and
+	// lineStateNU are returned after a break before a letter or digit,
+	// respectively.
+	if len(text) == 0 {
+		return -1, false, 0
+	}
+	switch c := text[0]; {
+	case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		if state >= 0 && state != lineStateAL {
+			return -1, false, 0
+		}
+	case c >= '0' && c <= '9':
+		if state >= 0 && state != lineStateNU {
+			return -1, false, 0
+		}
+	default:
+		return -1, false, 0
+	}
+
+	// Scan the letters and digits.
+	length = 1
+	for length < len(text) && isASCIIAlphanumeric(text[length]) {
+		length++
+	}
+
+	// Scan the spaces or the line feed.
+	end := length
+	for end < len(text) && text[end] == ' ' {
+		end++
+	}
+	if end < len(text) && text[end] == '\n' {
+		end++
+		mustBreak = true
+	}
+	if end == len(text) {
+		return end, true, lbcAny // LB3.
+	}
+	if end == length {
+		return -1, false, 0 // Something other than a space or a line feed.
+	}
+
+	// Determine the state after the first character of the next segment.
+	switch c := text[end]; {
+	case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		return end, mustBreak, lineStateAL
+	case c >= '0' && c <= '9':
+		return end, mustBreak, lineStateNU
+	}
+	return -1, false, 0
+}
+
+// isASCIIAlphanumeric returns true if the given byte is an ASCII letter or
+// digit.
+func isASCIIAlphanumeric(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// FirstLineSegmentStripEOL is like [FirstLineSegment] but the returned segment
+// does not include the line break characters (classes BK, CR, LF, and NL, see
+// [HasTrailingLineBreak]) which caused a mandatory break, e.g. "line." instead
+// of "line.\n". A CR LF sequence is removed as a whole. The "rest" slice still
+// starts after these characters. Whether the segment ended with a line break
+// can be determined from the "mustBreak" flag together with the length of the
+// "rest" slice (the end of the text is also a mandatory break).
+func FirstLineSegmentStripEOL(b []byte, state int) (segment, rest []byte, mustBreak bool, newState int) {
+	segment, rest, mustBreak, newState = FirstLineSegment(b, state)
+	if mustBreak {
+		segment = segment[:len(segment)-trailingLineBreakLength(segment, "")]
+	}
+	return
+}
+
+// FirstLineSegmentStripEOLInString is like [FirstLineSegmentStripEOL] but its
+// input and outputs are strings.
+func FirstLineSegmentStripEOLInString(str string, state int) (segment, rest string, mustBreak bool, newState int) {
+	segment, rest, mustBreak, newState = FirstLineSegmentInString(str, state)
+	if mustBreak {
+		segment = segment[:len(segment)-trailingLineBreakLength(nil, segment)]
+	}
+	return
+}
+
+// trailingLineBreakLength returns the length in bytes of the line break
+// characters (BK, CR, LF, NL, or CR LF) at the end of the given byte slice or
+// string (the string is used if the byte slice is nil). 0 is returned if there
+// are none.
+func trailingLineBreakLength(b []byte, str string) int {
+	var (
+		r      rune
+		length int
+		crlf   bool
+	)
+	if b != nil {
+		r, length = utf8.DecodeLastRune(b)
+		crlf = len(b) > 1 && b[len(b)-2] == '\r'
+	} else {
+		r, length = utf8.DecodeLastRuneInString(str)
+		crlf = len(str) > 1 && str[len(str)-2] == '\r'
+	}
+	switch property, _ := propertyLineBreak(r); property {
+	case prLF:
+		if crlf {
+			return length + 1
+		}
+		return length
+	case prBK, prCR, prNL:
+		return length
+	}
+	return 0
+}
+
+// NormalizeLineEndings replaces all line break characters which cause a
+// mandatory break (classes BK, CR, LF, and NL, see [HasTrailingLineBreak]) in
+// the given string with "to", e.g. "\n". This covers CR, LF, NEL (U+0085),
+// LINE SEPARATOR (U+2028), PARAGRAPH SEPARATOR (U+2029), vertical tab, and
+// form feed. A CR LF sequence is one line break and is replaced as a whole.
+// Combining marks following a line break character are kept (LB10).
+func NormalizeLineEndings(s string, to string) string {
+	var (
+		builder strings.Builder
+		segment string
+		must    bool
+	)
+	builder.Grow(len(s))
+	state := -1
+	for len(s) > 0 {
+		segment, s, must, state = FirstLineSegmentInString(s, state)
+		if !must {
+			builder.WriteString(segment)
+			continue
+		}
+		length := trailingLineBreakLength(nil, segment)
+		builder.WriteString(segment[:len(segment)-length])
+		if length > 0 {
+			builder.WriteString(to)
+		}
+	}
+	return builder.String()
+}
+
+// LineSegment is a segment of text between two line break opportunities, as
+// returned by [SplitLines].
+type LineSegment struct {
+	// Text is the text of the segment, including any trailing whitespace and
+	// line break characters.
+	Text string
+
+	// MustBreak indicates whether the line must be broken after the segment
+	// (true) or whether it may be broken (false). See [FirstLineSegment].
+	MustBreak bool
+}
+
+// SplitLines splits the given string into the segments between line break
+// opportunities, according to the rules of [Unicode Standard Annex #14]. This
+// is the same as calling [FirstLineSegmentInString] repeatedly and collecting
+// its results. The lines are not wrapped to a width, see [WrapString] for that.
+//
+// As with [FirstLineSegmentInString], the last segment always has MustBreak
+// set to true. An empty string results in no segments.
+//
+// [Unicode Standard Annex #14]: https://www.unicode.org/reports/tr14/
+func SplitLines(s string) []LineSegment {
+	var segments []LineSegment
+	state := -1
+	for len(s) > 0 {
+		var segment LineSegment
+		segment.Text, s, segment.MustBreak, state = FirstLineSegmentInString(s, state)
+		segments = append(segments, segment)
+	}
+	return segments
+}
+
+// AppendLineBreakOpportunities is like [AppendWordBoundaries] but appends the
+// byte offsets at which the line segments returned by
+// [FirstLineSegmentInString] end, i.e. the positions at which a line may or
+// must be broken.
+func AppendLineBreakOpportunities(dst []int, s string) []int {
+	var (
+		segment string
+		offset  int
+	)
+	state := -1
+	for len(s) > 0 {
+		segment, s, _, state = FirstLineSegmentInString(s, state)
+		offset += len(segment)
+		dst = append(dst, offset)
+	}
+	return dst
+}
+
+// LineBreak determines the line break opportunity before the rune "r", given
+// the state returned by the previous call. It lets you find line breaks in a
+// stream of runes without encoding them in UTF-8 first. The returned value is
+// one of [LineDontBreak], [LineCanBreak], or [LineMustBreak]. Note that the
+// end of the text is always a mandatory break (LB3).
+//
+// The "lookahead" function as well as the state are handled as described in
+// [WordBoundary]. The state used by this function is the same as the one used
+// by [FirstLineSegment] but it is not compatible with the state used by
+// [Step].
+func LineBreak(state int, r rune, lookahead func() (rune, bool)) (lineBreak int, newState int) {
+	// LB15.2 needs to know whether "r" is the last rune of the text.
+	next := lookaheadRunes(lookahead, func(rune) bool { return true })
+	newState, lineBreak = transitionLineBreakStateContext(state, r, nil, string(next))
+	return lineBreak, newState
+}
+
+// CanBreakBetween returns whether a line break is allowed between the two
+// given strings when the text "right" directly follows the text "left", e.g.
+// to decide whether two separately segmented pieces of text may be put on
+// different lines. The decision takes the context of both strings into
+// account, e.g. there is no break between "foo(" and "bar" but there is one
+// between "foo " and "bar". A break is never allowed at the start of the text,
+// i.e. if "left" is empty, and always at the end of the text, i.e. if "right"
+// is empty.
+//
+// Because the line break state depends on the entire text preceding the
+// junction, "left" is processed in its entirety. For repeated calls with the
+// same "left", consider iterating over the concatenated text with
+// [FirstLineSegmentInString] instead.
+func CanBreakBetween(left, right string) bool {
+	if left == "" {
+		return false
+	}
+	if right == "" {
+		return true
+	}
+	var (
+		segment string
+		offset  int
+	)
+	str, state := left+right, -1
+	for offset < len(left) {
+		segment, str, _, state = FirstLineSegmentInString(str, state)
+		offset += len(segment)
+	}
+	return offset == len(left)
+}
+
+// BreakOpportunityAt returns the kind of line break at the given byte offset
+// of s, i.e. before the byte at that offset: [LineDontBreak], [LineCanBreak],
+// or [LineMustBreak]. There is never a break at offset 0 and there is always a
+// mandatory break at the end of the text (LB3). LineDontBreak is also returned
+// for offsets which are not on a grapheme cluster boundary or which are
+// outside of s.
+//
+// The line break state depends on the entire text preceding the offset, so s
+// is processed from the start up to the offset, and slightly beyond for the
+// rules that look ahead. Querying many offsets of the same text this way takes
+// quadratic time. Use [FirstLineSegmentInString] or
+// [AppendLineBreakOpportunities] to find all break opportunities at once.
+func BreakOpportunityAt(s string, byteOffset int) int {
+	if byteOffset <= 0 || byteOffset > len(s) {
+		return LineDontBreak
+	}
+	var (
+		segment   string
+		offset    int
+		mustBreak bool
+	)
+	str, state := s, -1
+	for offset < byteOffset {
+		segment, str, mustBreak, state = FirstLineSegmentInString(str, state)
+		offset += len(segment)
+	}
+	if offset != byteOffset {
+		return LineDontBreak
+	}
+	if mustBreak {
+		return LineMustBreak
+	}
+	return LineCanBreak
+}
+
 // HasTrailingLineBreak returns true if the last rune in the given byte slice is
 // one of the hard line break code points defined in LB4 and LB5 of [UAX #14].
 //
@@ -1,6 +1,10 @@
 package runeseg
 
-import "testing"
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
 
 // Test all official Unicode test cases for line breaks using the byte slice
 // function.
@@ -181,6 +185,721 @@ func TestHasTrailingLineBreakInString(t *testing.T) {
 	}
 }
 
+// Test that LineBreak reproduces the output of FirstLineSegment for the
+// official Unicode test cases.
+func TestLineBreak(t *testing.T) {
+	for testNum, testCase := range lineBreakTestCases {
+		var expected []string
+		b, state := []byte(testCase.original), -1
+		for len(b) > 0 {
+			var segment []byte
+			segment, b, _, state = FirstLineSegment(b, state)
+			expected = append(expected, string(segment))
+		}
+
+		var segments []string
+		runes := []rune(testCase.original)
+		state = -1
+		for i, r := range runes {
+			var lineBreak int
+			lineBreak, state = LineBreak(state, r, runeLookahead(runes[i+1:]))
+			if i == 0 || lineBreak != LineDontBreak {
+				segments = append(segments, "")
+			}
+			segments[len(segments)-1] += string(r)
+		}
+
+		if len(segments) != len(expected) {
+			t.Errorf("Test case %d %q: got segments %q, FirstLineSegment returned %q", testNum, testCase.original, segments, expected)
+			continue
+		}
+		for i := range segments {
+			if segments[i] != expected[i] {
+				t.Errorf("Test case %d %q: segment %d is %q, FirstLineSegment returned %q", testNum, testCase.original, i, segments[i], expected[i])
+			}
+		}
+	}
+}
+
+// Test that the ideographic space U+3000 (class BA) provides a break
+// opportunity after it but not before it.
+func TestLineIdeographicSpace(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"\u3042\u3000\u3044", []string{"\u3042\u3000", "\u3044"}},
+		{"a\u3000b", []string{"a\u3000", "b"}},
+		{"\u3042\u3000\u3000\u3044", []string{"\u3042\u3000\u3000", "\u3044"}},
+		{"\u3000\u3044", []string{"\u3000", "\u3044"}},
+		{"(\u3000a)", []string{"(\u3000", "a)"}}, // Not SP, so LB14 ends after it.
+	} {
+		segments := lineSegmentsOf(testCase.original)
+		if strings.Join(segments, "|") != strings.Join(testCase.expected, "|") {
+			t.Errorf("%q: got segments %q, expected %q", testCase.original, segments, testCase.expected)
+		}
+	}
+}
+
+// Test that the separators U+2028 and U+2029, vertical tab, and form feed
+// (class BK) result in mandatory breaks (LB4).
+func TestLineSeparators(t *testing.T) {
+	for _, separator := range []string{"\u2028", "\u2029", "\v", "\f"} {
+		original := "a" + separator + "b"
+		var (
+			segments  []string
+			mustBreak bool
+		)
+		str, state := original, -1
+		for len(str) > 0 {
+			var segment string
+			segment, str, mustBreak, state = FirstLineSegmentInString(str, state)
+			segments = append(segments, segment)
+			if len(segments) == 1 && !mustBreak {
+				t.Errorf("%q: no mandatory break after %q", original, segment)
+			}
+		}
+		if len(segments) != 2 || segments[0] != "a"+separator || segments[1] != "b" {
+			t.Errorf("%q: got segments %q, expected %q", original, segments, []string{"a" + separator, "b"})
+		}
+		if !HasTrailingLineBreakInString("a" + separator) {
+			t.Errorf("%q: not recognized as a trailing line break", separator)
+		}
+
+		// The same applies to Step.
+		str, state = original, -1
+		var (
+			cluster    string
+			boundaries int
+		)
+		cluster, str, _, state = StepString(str, state)
+		cluster, _, boundaries, _ = StepString(str, state)
+		if cluster != separator || boundaries&MaskLine != LineMustBreak {
+			t.Errorf("%q: Step returned %q with line break %d, expected a mandatory break", original, cluster, boundaries&MaskLine)
+		}
+	}
+}
+
+// Test line breaks around mathematical operators. U+2212 MINUS SIGN is of
+// class PR, U+00D7 MULTIPLICATION SIGN and U+00F7 DIVISION SIGN are of class AI
+// (treated as AL), so none of them allows a break next to a letter or digit.
+func TestLineMathOperators(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"a\u2212b", []string{"a\u2212b"}},
+		{"1\u22122", []string{"1\u22122"}},
+		{"\u22125", []string{"\u22125"}}, // LB25: PR × NU.
+		{"3\u00d74", []string{"3\u00d74"}},
+		{"6\u00f72", []string{"6\u00f72"}},
+		{"a \u2212 b", []string{"a ", "\u2212 ", "b"}},
+		{"3 \u00d7 4", []string{"3 ", "\u00d7 ", "4"}},
+		{"1-2", []string{"1-2"}}, // For comparison: HY before a number (LB25).
+		{"a-b", []string{"a-", "b"}},
+	} {
+		segments := lineSegmentsOf(testCase.original)
+		if strings.Join(segments, "|") != strings.Join(testCase.expected, "|") {
+			t.Errorf("%q: got segments %q, expected %q", testCase.original, segments, testCase.expected)
+		}
+	}
+}
+
+// Test that the katakana middle dot U+30FB (class NS) does not start a line
+// (LB21) unless it follows a space (LB18).
+func TestLineKatakanaMiddleDot(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"\u6771\u4eac\u30fb\u5927\u962a", []string{"\u6771", "\u4eac\u30fb", "\u5927", "\u962a"}},
+		{"\u30c8\u30e0\u30fb\u30af", []string{"\u30c8", "\u30e0\u30fb", "\u30af"}},
+		{"a\u30fbb", []string{"a\u30fb", "b"}},
+		{"\u4eac \u30fb\u5927", []string{"\u4eac ", "\u30fb", "\u5927"}},
+	} {
+		segments := lineSegmentsOf(testCase.original)
+		if strings.Join(segments, "|") != strings.Join(testCase.expected, "|") {
+			t.Errorf("%q: got segments %q, expected %q", testCase.original, segments, testCase.expected)
+		}
+	}
+}
+
+// Test U+2019 RIGHT SINGLE QUOTATION MARK (class QU, general category Pf)
+// used as an apostrophe and as a closing quotation mark. LB19 prevents breaks
+// around it and LB15.2 attaches it to the preceding text at the end of the
+// text.
+func TestLineApostrophe(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"don\u2019t", []string{"don\u2019t"}},
+		{"I don\u2019t know", []string{"I ", "don\u2019t ", "know"}},
+		{"rock \u2019n\u2019 roll", []string{"rock ", "\u2019n\u2019 ", "roll"}},
+		{"he said \u2019hi\u2019", []string{"he ", "said ", "\u2019hi\u2019"}},
+		{"he said \u2018hi.\u2019", []string{"he ", "said ", "\u2018hi.\u2019"}},
+		{"he said \u2018hi.\u2019 Then", []string{"he ", "said ", "\u2018hi.\u2019 ", "Then"}},
+		{"x \u2019", []string{"x \u2019"}},             // LB15.2: SP × QU_Pf eot.
+		{"x \u2019 y", []string{"x ", "\u2019 ", "y"}}, // Not at the end of the text.
+	} {
+		segments := lineSegmentsOf(testCase.original)
+		if strings.Join(segments, "|") != strings.Join(testCase.expected, "|") {
+			t.Errorf("%q: got segments %q, expected %q", testCase.original, segments, testCase.expected)
+		}
+	}
+}
+
+// Test U+2015 HORIZONTAL BAR, used as a quotation dash to introduce dialogue.
+// Its class AI is resolved to AL (LB1), so unlike the em dash (B2), it does not
+// allow a break before or after it, except after following spaces (LB18).
+func TestLineHorizontalBar(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"\u2015Hello, she said.", []string{"\u2015Hello, ", "she ", "said."}},
+		{"\u2015", []string{"\u2015"}},
+		{"Yes.\n\u2015Why?", []string{"Yes.\n", "\u2015Why?"}},
+		{"yes \u2015 no", []string{"yes ", "\u2015 ", "no"}},
+		{"yes\u2015no", []string{"yes\u2015no"}},
+		{"yes\u2014no", []string{"yes", "\u2014", "no"}}, // Em dash for comparison.
+		{"said \u2015then", []string{"said ", "\u2015then"}},
+		{"\u2015\u2015", []string{"\u2015\u2015"}},
+	} {
+		segments := lineSegmentsOf(testCase.original)
+		if strings.Join(segments, "|") != strings.Join(testCase.expected, "|") {
+			t.Errorf("%q: got segments %q, expected %q", testCase.original, segments, testCase.expected)
+		}
+	}
+}
+
+// Test the section sign U+00A7 and the numero sign U+2116 before numbers. The
+// section sign's class AI resolves to AL (LB1), so "§5" stays together (LB23).
+// The numero sign is PR, so "№12" stays together (LB25). A space after either
+// sign allows a break (LB18); use U+00A0 NO-BREAK SPACE (GL) to prevent it.
+func TestLineSectionNumeroSigns(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"\u00a75", []string{"\u00a75"}},
+		{"\u00a75.1", []string{"\u00a75.1"}},
+		{"\u00a7\u00a75", []string{"\u00a7\u00a75"}},
+		{"see \u00a75", []string{"see ", "\u00a75"}},
+		{"\u00a7 5", []string{"\u00a7 ", "5"}},
+		{"\u00a7\u00a05", []string{"\u00a7\u00a05"}},
+		{"5\u00a7", []string{"5\u00a7"}},
+		{"\u211612", []string{"\u211612"}},
+		{"(\u211612)", []string{"(\u211612)"}},
+		{"no. \u211612", []string{"no. ", "\u211612"}},
+		{"\u2116 12", []string{"\u2116 ", "12"}},
+		{"\u2116\u00a012", []string{"\u2116\u00a012"}},
+	} {
+		segments := lineSegmentsOf(testCase.original)
+		if strings.Join(segments, "|") != strings.Join(testCase.expected, "|") {
+			t.Errorf("%q: got segments %q, expected %q", testCase.original, segments, testCase.expected)
+		}
+	}
+}
+
+// Test LB23a: PR × (ID | EB | EM) and (ID | EB | EM) × PO with emoji, which
+// are ID or EB, and with unassigned Extended Pictographic code points. Emoji
+// and numbers are not kept together (LB23 covers only letters and numbers),
+// and there is no rule keeping an emoji and a following PR or a preceding PO
+// together.
+func TestLineEmojiPrefixPostfix(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"\U0001F381%", []string{"\U0001F381%"}},                     // ID × PO.
+		{"$\U0001F381", []string{"$\U0001F381"}},                     // PR × ID.
+		{"\u20ac\U0001F381", []string{"\u20ac\U0001F381"}},           // PR × ID.
+		{"\U0001F44D\U0001F3FD%", []string{"\U0001F44D\U0001F3FD%"}}, // EB EM × PO.
+		{"$\U0001F44D\U0001F3FD", []string{"$\U0001F44D\U0001F3FD"}}, // PR × EB.
+		{"\U0001FC00%", []string{"\U0001FC00%"}},                     // Unassigned Extended Pictographic × PO.
+		{"$\U0001FC00", []string{"$\U0001FC00"}},
+		{"\U0001F381$", []string{"\U0001F381", "$"}},
+		{"%\U0001F381", []string{"%", "\U0001F381"}},
+		{"\U0001F38150", []string{"\U0001F381", "50"}},
+		{"50\U0001F381", []string{"50", "\U0001F381"}},
+		{"50%\U0001F381", []string{"50%", "\U0001F381"}},
+		{"$\U0001F38150", []string{"$\U0001F381", "50"}},
+		{"\U0001F381 50%", []string{"\U0001F381 ", "50%"}},
+	} {
+		segments := lineSegmentsOf(testCase.original)
+		if strings.Join(segments, "|") != strings.Join(testCase.expected, "|") {
+			t.Errorf("%q: got segments %q, expected %q", testCase.original, segments, testCase.expected)
+		}
+	}
+}
+
+// Test LB12a: [^SP BA HY] × GL. A no-break space or another glue character
+// (GL) does not allow a break before it, unless it follows a space, a hyphen,
+// or another character of class BA, after which a break is allowed. The break
+// after a regular space is a normal LB18 break.
+func TestLineGlue(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"a\u00a0b", []string{"a\u00a0b"}},                 // LB12a and LB12.
+		{"a \u00a0b", []string{"a ", "\u00a0b"}},           // LB18: After a space.
+		{"a- \u00a0b", []string{"a- ", "\u00a0b"}},         // LB18: After a hyphen and a space.
+		{"a-\u00a0b", []string{"a-", "\u00a0b"}},           // After HY.
+		{"a\u2010\u00a0b", []string{"a\u2010", "\u00a0b"}}, // After BA (HYPHEN).
+		{"a\u00ad\u00a0b", []string{"a\u00ad", "\u00a0b"}}, // After BA (SOFT HYPHEN).
+		{"a\u2009\u00a0b", []string{"a\u2009", "\u00a0b"}}, // After BA (THIN SPACE).
+		{"a\u00a0\u00a0b", []string{"a\u00a0\u00a0b"}},
+		{"\u00a0b", []string{"\u00a0b"}},
+		{"1\u00a0000", []string{"1\u00a0000"}},
+		{"a\u202fb", []string{"a\u202fb"}}, // NARROW NO-BREAK SPACE.
+		{"a \u202fb", []string{"a ", "\u202fb"}},
+		{"a\u2007b", []string{"a\u2007b"}},   // FIGURE SPACE.
+		{"a \u2060b", []string{"a \u2060b"}}, // LB11: Never break before WORD JOINER.
+	} {
+		segments := lineSegmentsOf(testCase.original)
+		if strings.Join(segments, "|") != strings.Join(testCase.expected, "|") {
+			t.Errorf("%q: got segments %q, expected %q", testCase.original, segments, testCase.expected)
+		}
+	}
+}
+
+// Test LB16: (CL | CP) SP* × NS. The break before a nonstarter is prevented
+// after any number of closing punctuation characters and spaces. U+3002
+// IDEOGRAPHIC FULL STOP is itself CL while small kana (CJ) resolve to NS.
+func TestLineCloseNonstarter(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"))\u3005", []string{"))\u3005"}},
+		{")) \u3005", []string{")) \u3005"}},
+		{"])   \u3041", []string{"])   \u3041"}},
+		{"(a)) \u3005x", []string{"(a)) \u3005", "x"}},
+		{"a) ) \u301c", []string{"a) ) \u301c"}},
+		{"\uff09\u300d \u30fc", []string{"\uff09\u300d \u30fc"}},
+		{"])\u3002", []string{"])\u3002"}},           // U+3002 is CL (LB13).
+		{"\u3002 \u3041", []string{"\u3002 \u3041"}}, // After U+3002.
+		{"a \u3005", []string{"a ", "\u3005"}},       // No closing punctuation.
+		{")) a \u3005", []string{")) ", "a ", "\u3005"}},
+	} {
+		segments := lineSegmentsOf(testCase.original)
+		if strings.Join(segments, "|") != strings.Join(testCase.expected, "|") {
+			t.Errorf("%q: got segments %q, expected %q", testCase.original, segments, testCase.expected)
+		}
+	}
+}
+
+// Test Japanese small kana and the prolonged sound mark, which are CJ and
+// resolve to NS (LB1). They may not start a line (LB21), so they stay attached
+// to the preceding character, but a break is allowed after them.
+func TestLineSmallKana(t *testing.T) {
+	for _, r := range []rune{0x3041, 0x3063, 0x3083, 0x3087, 0x30a1, 0x30c3, 0x30e3, 0x30e5, 0x30fc, 0x31f0} {
+		if prop, _ := propertyLineBreak(r); prop != prCJ {
+			t.Errorf("%U: got line break property %d, expected CJ", r, prop)
+		}
+	}
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"\u3061\u3087\u3063\u3068", []string{"\u3061\u3087\u3063", "\u3068"}}, // "Chotto".
+		{"\u3061\u3083\u3063", []string{"\u3061\u3083\u3063"}},
+		{"\u30ad\u30e3\u30c3\u30b7\u30e5", []string{"\u30ad\u30e3\u30c3", "\u30b7\u30e5"}}, // "Kyasshu".
+		{"\u30b3\u30fc\u30d2\u30fc", []string{"\u30b3\u30fc", "\u30d2\u30fc"}},             // "Koohii".
+		{"\u6771\u4eac\u3063", []string{"\u6771", "\u4eac\u3063"}},
+		{"\u30a1\u30a3\u30a5", []string{"\u30a1\u30a3\u30a5"}}, // Several small kana.
+		{"\u31f0\u31f1", []string{"\u31f0\u31f1"}},             // Katakana phonetic extensions.
+		{"\u300c\u3087", []string{"\u300c\u3087"}},             // LB14: After an opening bracket.
+		{"\u3087\u300d", []string{"\u3087\u300d"}},
+		{"\u3087", []string{"\u3087"}},
+		{"a \u3087", []string{"a ", "\u3087"}}, // LB18: Spaces allow a break.
+	} {
+		segments := lineSegmentsOf(testCase.original)
+		if strings.Join(segments, "|") != strings.Join(testCase.expected, "|") {
+			t.Errorf("%q: got segments %q, expected %q", testCase.original, segments, testCase.expected)
+		}
+	}
+}
+
+// Test that LB8a (ZWJ ×) only prevents the break directly after a zero width
+// joiner. A following space ends its effect so that LB18 applies as usual.
+func TestLineZWJSpace(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"\U0001F44D\u200d b", []string{"\U0001F44D\u200d ", "b"}},
+		{"\U0001F44D\u200d  b", []string{"\U0001F44D\u200d  ", "b"}},
+		{"a\u200d b", []string{"a\u200d ", "b"}},
+		{"\U0001F44D\u200d\u4e16 \u754c", []string{"\U0001F44D\u200d\u4e16 ", "\u754c"}},
+		{"\u4e16\u200d\u754c", []string{"\u4e16\u200d\u754c"}},
+		{"\u4e16\u200d \u754c", []string{"\u4e16\u200d ", "\u754c"}},
+	} {
+		segments := lineSegmentsOf(testCase.original)
+		if strings.Join(segments, "|") != strings.Join(testCase.expected, "|") {
+			t.Errorf("%q: got segments %q, expected %q", testCase.original, segments, testCase.expected)
+		}
+	}
+}
+
+// Test that a zero width joiner between letters prevents a line break (LB8a),
+// also when it follows a space or a zero width space where it is treated as AL
+// (LB10), and that its effect ends at the next break opportunity.
+func TestLineZWJLetters(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"a\u200db", []string{"a\u200db"}},
+		{"a\u200d\u200db", []string{"a\u200d\u200db"}},
+		{"a\u200db c", []string{"a\u200db ", "c"}},
+		{"a\u200d-b", []string{"a\u200d-", "b"}},
+		{"a-\u200db", []string{"a-\u200db"}},
+		{"a \u200db", []string{"a ", "\u200db"}},
+		{"a \u200d b", []string{"a ", "\u200d ", "b"}},
+		{"a\u200b\u200db", []string{"a\u200b", "\u200db"}},
+		{"a\u200b \u200db", []string{"a\u200b ", "\u200db"}},
+		{"x\u200d\n\u200dy", []string{"x\u200d\n", "\u200dy"}},
+	} {
+		segments := lineSegmentsOf(testCase.original)
+		if strings.Join(segments, "|") != strings.Join(testCase.expected, "|") {
+			t.Errorf("%q: got segments %q, expected %q", testCase.original, segments, testCase.expected)
+		}
+	}
+
+	// The same with Step, which reports the opportunities at grapheme cluster
+	// boundaries.
+	for _, testCase := range []struct {
+		original string
+		expected []int // Line break decisions after each grapheme cluster.
+	}{
+		{"a\u200db c", []int{LineDontBreak, LineDontBreak, LineCanBreak, LineMustBreak}},
+		{"a\u200bb", []int{LineDontBreak, LineCanBreak, LineMustBreak}},
+		{"a\u200b\u200db", []int{LineDontBreak, LineCanBreak, LineDontBreak, LineMustBreak}},
+	} {
+		var decisions []int
+		str, state := testCase.original, -1
+		for len(str) > 0 {
+			var boundaries int
+			_, str, boundaries, state = StepString(str, state)
+			decisions = append(decisions, boundaries&MaskLine)
+		}
+		if len(decisions) != len(testCase.expected) {
+			t.Errorf("%q: got line breaks %v, expected %v", testCase.original, decisions, testCase.expected)
+			continue
+		}
+		for index, decision := range decisions {
+			if decision != testCase.expected[index] {
+				t.Errorf("%q: got line breaks %v, expected %v", testCase.original, decisions, testCase.expected)
+				break
+			}
+		}
+	}
+}
+
+// Test the CanBreakBetween function.
+func TestCanBreakBetween(t *testing.T) {
+	for _, testCase := range []struct {
+		left, right string
+		expected    bool
+	}{
+		{"foo(", "bar", false},
+		{"foo ", "bar", true},
+		{"foo", "bar", false},
+		{"foo", " bar", false},              // LB7: No break before spaces.
+		{"foo\n", "bar", true},              // Mandatory break.
+		{"a", "\u0301", false},              // Combining mark.
+		{"\u4e16", "\u754c", true},          // Ideographs.
+		{"$", "100", false},                 // LB25: PR × NU.
+		{"100", "%", false},                 // LB25: NU × PO.
+		{"\U0001F1E9", "\U0001F1EA", false}, // LB30a: Regional indicator pair.
+		{"\U0001F1E9\U0001F1EA", "\U0001F1EB", true},
+		{"", "x", false},
+		{"x", "", true},
+		{"", "", false},
+	} {
+		if result := CanBreakBetween(testCase.left, testCase.right); result != testCase.expected {
+			t.Errorf("CanBreakBetween(%q, %q) is %t, expected %t", testCase.left, testCase.right, result, testCase.expected)
+		}
+	}
+
+	// Compare with the segments of the full text at every rune boundary.
+	for _, original := range []string{benchmarkStr, "foo(bar) baz\nqux \u201cquoted\u201d text", "\u4e16\u754c\u3002 50% off!"} {
+		breaks := make(map[int]bool)
+		var offset int
+		for _, segment := range SplitLines(original) {
+			offset += len(segment.Text)
+			breaks[offset] = true
+		}
+		for pos := range original {
+			if pos == 0 {
+				continue
+			}
+			if result := CanBreakBetween(original[:pos], original[pos:]); result != breaks[pos] {
+				t.Errorf("CanBreakBetween(%q, %q) is %t, expected %t", original[:pos], original[pos:], result, breaks[pos])
+			}
+		}
+	}
+}
+
+// Test the BreakOpportunityAt function.
+func TestBreakOpportunityAt(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		offset   int
+		expected int
+	}{
+		{"foo bar", 4, LineCanBreak},  // Word boundary after a space.
+		{"foo bar", 3, LineDontBreak}, // LB7: No break before spaces.
+		{"foo bar", 2, LineDontBreak}, // Mid-word.
+		{"foo\nbar", 4, LineMustBreak},
+		{"foo\r\nbar", 4, LineDontBreak}, // LB5: CR × LF.
+		{"foo\r\nbar", 5, LineMustBreak},
+		{"foo(bar)", 4, LineDontBreak},
+		{"\u4e16\u754c", 3, LineCanBreak},
+		{"e\u0301x", 1, LineDontBreak}, // Inside a grapheme cluster.
+		{"\u4e16", 1, LineDontBreak},   // Inside a UTF-8 sequence.
+		{"foo bar", 0, LineDontBreak},
+		{"foo bar", 7, LineMustBreak}, // LB3.
+		{"foo bar", 8, LineDontBreak},
+		{"foo bar", -1, LineDontBreak},
+		{"", 0, LineDontBreak},
+	} {
+		if result := BreakOpportunityAt(testCase.original, testCase.offset); result != testCase.expected {
+			t.Errorf("BreakOpportunityAt(%q, %d) is %d, expected %d", testCase.original, testCase.offset, result, testCase.expected)
+		}
+	}
+
+	// Compare with the segments of the full text at every byte offset.
+	for _, original := range []string{benchmarkStr, "foo(bar) baz\nqux \u201cquoted\u201d text\r\n", "\u4e16\u754c\u3002 50% off!"} {
+		breaks := make(map[int]int)
+		var offset int
+		for _, segment := range SplitLines(original) {
+			offset += len(segment.Text)
+			breaks[offset] = LineCanBreak
+			if segment.MustBreak {
+				breaks[offset] = LineMustBreak
+			}
+		}
+		for pos := 0; pos <= len(original); pos++ {
+			if result := BreakOpportunityAt(original, pos); result != breaks[pos] {
+				t.Errorf("BreakOpportunityAt(%q, %d) is %d, expected %d", original, pos, result, breaks[pos])
+			}
+		}
+	}
+}
+
+// Test the removal of line break characters from line segments.
+func TestFirstLineSegmentStripEOL(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+		breaks   []bool
+	}{
+		{"a\nb", []string{"a", "b"}, []bool{true, true}},
+		{"a\r\nb", []string{"a", "b"}, []bool{true, true}},
+		{"a\rb", []string{"a", "b"}, []bool{true, true}},
+		{"a\r\r\nb", []string{"a", "", "b"}, []bool{true, true, true}},
+		{"a\n\n", []string{"a", ""}, []bool{true, true}},
+		{"a\u0085b\u2028c\vd", []string{"a", "b", "c", "d"}, []bool{true, true, true, true}},
+		{"First line.\nSecond line.", []string{"First ", "line.", "Second ", "line."}, []bool{false, true, false, true}},
+		{"a \n", []string{"a "}, []bool{true}}, // Spaces are kept.
+	} {
+		var (
+			segments, bytesSegments []string
+			breaks                  []bool
+		)
+		str, state := testCase.original, -1
+		for len(str) > 0 {
+			var (
+				segment   string
+				mustBreak bool
+			)
+			segment, str, mustBreak, state = FirstLineSegmentStripEOLInString(str, state)
+			segments = append(segments, segment)
+			breaks = append(breaks, mustBreak)
+		}
+		b, state := []byte(testCase.original), -1
+		for len(b) > 0 {
+			var segment []byte
+			segment, b, _, state = FirstLineSegmentStripEOL(b, state)
+			bytesSegments = append(bytesSegments, string(segment))
+		}
+		if strings.Join(segments, "|") != strings.Join(testCase.expected, "|") || len(segments) != len(testCase.expected) {
+			t.Errorf("%q: got segments %q, expected %q", testCase.original, segments, testCase.expected)
+			continue
+		}
+		if strings.Join(bytesSegments, "|") != strings.Join(segments, "|") {
+			t.Errorf("%q: got byte segments %q, expected %q", testCase.original, bytesSegments, segments)
+		}
+		for i := range breaks {
+			if breaks[i] != testCase.breaks[i] {
+				t.Errorf("%q: segment %d has mustBreak %t, expected %t", testCase.original, i, breaks[i], testCase.breaks[i])
+			}
+		}
+	}
+}
+
+// Test the normalization of line endings.
+func TestNormalizeLineEndings(t *testing.T) {
+	for _, testCase := range []struct {
+		original, to, expected string
+	}{
+		{"", "\n", ""},
+		{"abc", "\n", "abc"},
+		{"a\nb", "\n", "a\nb"},
+		{"a\r\nb", "\n", "a\nb"},
+		{"a\rb", "\n", "a\nb"},
+		{"a\r\r\nb", "\n", "a\n\nb"},
+		{"a\n\rb", "\n", "a\n\nb"},
+		{"a\r\nb\rc\nd\u0085e\u2028f\u2029g", "\n", "a\nb\nc\nd\ne\nf\ng"},
+		{"a\r\nb\rc\nd\u0085e\u2028f\u2029g", "\r\n", "a\r\nb\r\nc\r\nd\r\ne\r\nf\r\ng"},
+		{"a\nb\r\n", "", "ab"},
+		{"a\vb\fc", "\n", "a\nb\nc"},
+		{"First line.  \r\nSecond line.\r\n", "\n", "First line.  \nSecond line.\n"},
+		{"\n\n\n", "\r\n", "\r\n\r\n\r\n"},
+		{"a\n\u0301b", "\r\n", "a\r\n\u0301b"},             // The combining mark is kept.
+		{"a\u200bb\u00a0c\td", "\n", "a\u200bb\u00a0c\td"}, // No mandatory breaks.
+	} {
+		if normalized := NormalizeLineEndings(testCase.original, testCase.to); normalized != testCase.expected {
+			t.Errorf("NormalizeLineEndings(%q, %q) is %q, expected %q", testCase.original, testCase.to, normalized, testCase.expected)
+		}
+	}
+}
+
+// Test the SplitLines function.
+func TestSplitLines(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []LineSegment
+	}{
+		{"", nil},
+		{"First line.\nSecond line.", []LineSegment{
+			{"First ", false},
+			{"line.\n", true},
+			{"Second ", false},
+			{"line.", true},
+		}},
+		{"a\r\n\nb ", []LineSegment{
+			{"a\r\n", true},
+			{"\n", true},
+			{"b ", true},
+		}},
+	} {
+		segments := SplitLines(testCase.original)
+		if len(segments) != len(testCase.expected) {
+			t.Errorf("%q: got segments %v, expected %v", testCase.original, segments, testCase.expected)
+			continue
+		}
+		for i := range segments {
+			if segments[i] != testCase.expected[i] {
+				t.Errorf("%q: segment %d is %v, expected %v", testCase.original, i, segments[i], testCase.expected[i])
+			}
+		}
+	}
+}
+
+// firstLineSegmentEngine is FirstLineSegmentInString without the ASCII fast
+// path, i.e. it always uses the full state machine.
+func firstLineSegmentEngine(str string, state int) (segment, rest string, mustBreak bool, newState int) {
+	if len(str) == 0 {
+		return
+	}
+	r, length := utf8.DecodeRuneInString(str)
+	if len(str) <= length {
+		return str, "", true, lbcAny
+	}
+	if state < 0 {
+		state, _ = transitionLineBreakStateContext(state, r, nil, str[length:])
+	}
+	var boundary int
+	for {
+		r, l := utf8.DecodeRuneInString(str[length:])
+		state, boundary = transitionLineBreakStateContext(state, r, nil, str[length+l:])
+		if boundary != LineDontBreak {
+			return str[:length], str[length:], boundary == LineMustBreak, state
+		}
+		length += l
+		if len(str) <= length {
+			return str, "", true, lbcAny
+		}
+	}
+}
+
+// checkASCIIFastPath compares the results of FirstLineSegmentInString and
+// FirstLineSegment with those of the full state machine for the given string.
+func checkASCIIFastPath(t *testing.T, original string) {
+	t.Helper()
+	str, b, state := original, []byte(original), -1
+	for len(str) > 0 {
+		expected, rest, mustBreak, newState := firstLineSegmentEngine(str, state)
+		segment, restStr, mustBreakStr, newStateStr := FirstLineSegmentInString(str, state)
+		if segment != expected || restStr != rest || mustBreakStr != mustBreak || newStateStr != newState {
+			t.Fatalf("%q: FirstLineSegmentInString(%q, %d) returned (%q, %v, %d), expected (%q, %v, %d)", original, str, state, segment, mustBreakStr, newStateStr, expected, mustBreak, newState)
+		}
+		segmentBytes, restBytes, mustBreakBytes, newStateBytes := FirstLineSegment(b, state)
+		if string(segmentBytes) != expected || string(restBytes) != rest || mustBreakBytes != mustBreak || newStateBytes != newState {
+			t.Fatalf("%q: FirstLineSegment(%q, %d) returned (%q, %v, %d), expected (%q, %v, %d)", original, b, state, segmentBytes, mustBreakBytes, newStateBytes, expected, mustBreak, newState)
+		}
+		str, b, state = rest, restBytes, newState
+	}
+}
+
+// Test that the ASCII fast path of the line break functions returns the same
+// results as the full state machine, for all short strings made of letters,
+// digits, spaces, newlines, and some characters which are not handled by the
+// fast path.
+func TestLineASCIIFastPath(t *testing.T) {
+	const alphabet = "aZ5 \n.(-\u00e9"
+	runes := []rune(alphabet)
+	var generate func(prefix string, n int)
+	generate = func(prefix string, n int) {
+		checkASCIIFastPath(t, prefix)
+		if n == 0 {
+			return
+		}
+		for _, r := range runes {
+			generate(prefix+string(r), n-1)
+		}
+	}
+	generate("", 6)
+	for _, testCase := range lineBreakTestCases {
+		checkASCIIFastPath(t, testCase.original)
+	}
+	checkASCIIFastPath(t, benchmarkStr)
+}
+
+// Fuzz the ASCII fast path of the line break functions.
+func FuzzLineASCIIFastPath(f *testing.F) {
+	f.Add("The quick brown fox\njumps over 13 lazy dogs")
+	f.Add("a  b\n\nc 1a2 \n")
+	f.Add("x\n\u201c (y)")
+	f.Fuzz(checkASCIIFastPath)
+}
+
+// Test the AppendLineBreakOpportunities function.
+func TestAppendLineBreakOpportunities(t *testing.T) {
+	for testNum, testCase := range lineBreakTestCases {
+		var (
+			expected []int
+			offset   int
+		)
+		for _, segment := range SplitLines(testCase.original) {
+			offset += len(segment.Text)
+			expected = append(expected, offset)
+		}
+		if offsets := AppendLineBreakOpportunities(nil, testCase.original); !equalOffsets(offsets, expected) {
+			t.Errorf("Test case %d %q: got offsets %v, expected %v", testNum, testCase.original, offsets, expected)
+		}
+	}
+	offsets := AppendLineBreakOpportunities([]int{-1}, "foo(bar) baz\nqux")
+	if !equalOffsets(offsets, []int{-1, 9, 13, 16}) {
+		t.Errorf("Got offsets %v", offsets)
+	}
+}
+
 // Benchmark the use of the line break function for byte slices.
 func BenchmarkLineFunctionBytes(b *testing.B) {
 	for i := 0; i < b.N; i++ {
@@ -206,3 +925,27 @@ func BenchmarkLineFunctionString(b *testing.B) {
 		}
 	}
 }
+
+// Benchmark the line break function for strings on ASCII text, with and
+// without the ASCII fast path.
+func BenchmarkLineFunctionASCII(b *testing.B) {
+	text := strings.Repeat("The quick brown fox jumps over the lazy dog and 42 other animals\n", 100)
+	for _, bm := range []struct {
+		name string
+		f    func(string, int) (string, string, bool, int)
+	}{
+		{"FastPath", FirstLineSegmentInString},
+		{"Engine", firstLineSegmentEngine},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			for i := 0; i < b.N; i++ {
+				state := -1
+				str := text
+				for len(str) > 0 {
+					_, str, _, state = bm.f(str, state)
+				}
+			}
+		})
+	}
+}
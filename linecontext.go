@@ -41,6 +41,9 @@ const (
 	// After CP with East Asian width F/W/H (for LB30)
 	lbCtxCPeaFWH = 1 << 5
 
+	// Inside a numeric sequence NU (NU|SY|IS)* (CL|CP)? (for LB25)
+	lbCtxNumeric = 1 << 6
+
 	// Saw Virama in Aksara sequence
 	lbCtxAksaraVirama = 1 << 7
 )
@@ -106,7 +109,7 @@ const (
 	lbcQUPi         // QU with Pi (initial quotation)
 	lbcQUPiSP       // QU_Pi followed by SP
 	lbcZWSP         // ZW followed by SP (for LB8)
-	lbcHLHY         // HL followed by HY (for LB21a)
+	lbcHLHY         // HL followed by HY or HH (for LB21a)
 	lbcSotHY        // HY at start of text (for LB20a)
 	lbcSotHH        // HH at start of text (for LB20a)
 	lbcDottedCircle // Dotted Circle (U+25CC) - acts like AL but also like AK for Aksara
@@ -183,8 +186,8 @@ func transitionLineBreakContext(ctx LineContext, r rune, b []byte, str string) (
 	if prop == prCM || prop == prZWJ {
 		// LB9: Don't break before CM/ZWJ (treat X CM* as X)
 		// States where we can't attach CM (need LB10 instead)
-		isSpaceLike := ctx.State == lbcSP || ctx.State == lbcB2SP || ctx.State == lbcQUSP || ctx.State == lbcCLCP || ctx.State == lbcZWSP
-		isMandatoryBreak := ctx.State == lbcBK || ctx.State == lbcCR || ctx.State == lbcLF || ctx.State == lbcNL || ctx.State == lbcZW
+		isSpaceLike := ctx.State == lbcSP || ctx.State == lbcB2SP || ctx.State == lbcQUSP || ctx.State == lbcCLCP || ctx.State == lbcZW || ctx.State == lbcZWSP || ctx.State == lbcOPSP
+		isMandatoryBreak := ctx.State == lbcBK || ctx.State == lbcCR || ctx.State == lbcLF || ctx.State == lbcNL
 		isInitial := ctx.State == lbcAny
 
 		if !isSpaceLike && !isMandatoryBreak && !isInitial {
@@ -206,6 +209,10 @@ func transitionLineBreakContext(ctx LineContext, r rune, b []byte, str string) (
 		if isMandatoryBreak {
 			return newCtx, LineMustBreak
 		}
+		if ctx.State == lbcOPSP {
+			// LB14: OP SP* × (the CM is now AL, the OP context ends here)
+			return newCtx, LineDontBreak
+		}
 		if isSpaceLike {
 			return newCtx, LineCanBreak
 		}
@@ -217,7 +224,7 @@ func transitionLineBreakContext(ctx LineContext, r rune, b []byte, str string) (
 
 	// LB4: BK !
 	if ctx.State == lbcBK {
-		return applyBreak(ctx, prop, r, genCat, LineMustBreak)
+		return applyMandatoryBreak(ctx, prop, r, genCat)
 	}
 
 	// LB5: CR × LF, CR !, LF !, NL !
@@ -226,10 +233,10 @@ func transitionLineBreakContext(ctx LineContext, r rune, b []byte, str string) (
 			newCtx := nextContext(ctx, lbcLF, prop, r, genCat)
 			return newCtx, LineDontBreak
 		}
-		return applyBreak(ctx, prop, r, genCat, LineMustBreak)
+		return applyMandatoryBreak(ctx, prop, r, genCat)
 	}
 	if ctx.State == lbcLF || ctx.State == lbcNL {
-		return applyBreak(ctx, prop, r, genCat, LineMustBreak)
+		return applyMandatoryBreak(ctx, prop, r, genCat)
 	}
 
 	// LB6: × (BK | CR | LF | NL) - don't break before hard breaks
@@ -413,7 +420,11 @@ func transitionLineBreakContext(ctx LineContext, r rune, b []byte, str string) (
 	// LB21: × BA, × HY, × NS, BB ×
 	// LB21.02 (Unicode 17.0): × HH (Unambiguous_Hyphen)
 	if prop == prBA || prop == prHY || prop == prNS || prop == prHH {
-		newCtx := nextContext(ctx, propToState(prop), prop, r, genCat)
+		state := propToState(prop)
+		if ctx.State == lbcHL && (prop == prHY || prop == prHH) {
+			state = lbcHLHY // Remember for LB21a.
+		}
+		newCtx := nextContext(ctx, state, prop, r, genCat)
 		return newCtx, LineDontBreak
 	}
 
@@ -423,16 +434,13 @@ func transitionLineBreakContext(ctx LineContext, r rune, b []byte, str string) (
 		return newCtx, LineDontBreak
 	}
 
-	// LB21a: HL (HY|BA) ×
+	// LB21a (Unicode 16.0+): HL (HY|HH) × [^HL]
+	// Before another Hebrew letter, the default LB31 applies.
 	if ctx.State == lbcHLHY {
 		newCtx := nextContext(ctx, propToState(prop), prop, r, genCat)
-		return newCtx, LineDontBreak
-	}
-
-	// Track HL followed by HY/BA for LB21a
-	if ctx.State == lbcHL && (prop == prHY || prop == prBA) {
-		// Don't break, and remember for LB21a
-		newCtx := LineContext{State: lbcHLHY, Flags: ctx.Flags}
+		if prop == prHL {
+			return newCtx, LineCanBreak
+		}
 		return newCtx, LineDontBreak
 	}
 
@@ -680,6 +688,18 @@ func applyBreak(ctx LineContext, prop int, r rune, genCat int, breakType int) (L
 	return newCtx, breakType
 }
 
+// applyMandatoryBreak applies a mandatory break and returns the new context.
+// The character following a mandatory break starts a new line, so an initial
+// quotation mark there is treated like one at the start of text (LB15.1).
+func applyMandatoryBreak(ctx LineContext, prop int, r rune, genCat int) (LineContext, int) {
+	newCtx, breakType := applyBreak(ctx, prop, r, genCat, LineMustBreak)
+	if prop == prQU && genCat == gcPi {
+		newCtx.State = lbcQUPi
+		newCtx.Flags |= lbCtxSot | lbCtxAfterQUPi
+	}
+	return newCtx, breakType
+}
+
 // nextContext creates the next context after seeing a character.
 func nextContext(ctx LineContext, newState, prop int, r rune, genCat int) LineContext {
 	newCtx := LineContext{
@@ -707,6 +727,17 @@ func nextContext(ctx LineContext, newState, prop int, r rune, genCat int) LineCo
 		}
 	}
 
+	// Track numeric sequences for LB25: NU (NU|SY|IS)* (CL|CP)?
+	switch {
+	case prop == prNU:
+		newCtx.Flags |= lbCtxNumeric
+	case (prop == prSY || prop == prIS || prop == prCL || prop == prCP) && ctx.Flags&lbCtxNumeric != 0 &&
+		(ctx.State == lbcNU || ctx.State == lbcSY || ctx.State == lbcIS):
+		// Still part of the numeric sequence.
+	default:
+		newCtx.Flags &^= lbCtxNumeric
+	}
+
 	// Track word-initial position for LB20a
 	// LB20a: (sot|BK|CR|LF|NL|SP|ZW|CB|GL)(HY|HH) × (AL|HL)
 	// Set flag when HY/HH follows one of the allowed predecessor classes
@@ -840,10 +871,14 @@ func applyLB25(ctx LineContext, prop int) int {
 	// LB25 simplified versions:
 	// NU × (PO|PR) - don't break between number and postfix/prefix
 	// (PO|PR|HY|IS|NU) × NU - don't break before number
+	// NU (NU|SY|IS)* (CL|CP)? × (PO|PR) - tracked with lbCtxNumeric
 	// Note: SY×NU requires numeric context (NU preceding SY) which we don't fully track
 	switch {
 	case ctx.State == lbcNU && (prop == prPO || prop == prPR):
 		return LineDontBreak
+	case (ctx.State == lbcSY || ctx.State == lbcIS || ctx.State == lbcCL || ctx.State == lbcCP) &&
+		ctx.Flags&lbCtxNumeric != 0 && (prop == prPO || prop == prPR):
+		return LineDontBreak
 	case (ctx.State == lbcPO || ctx.State == lbcPR || ctx.State == lbcHY || ctx.State == lbcIS || ctx.State == lbcNU) && prop == prNU:
 		return LineDontBreak
 	}
@@ -1,6 +1,7 @@
 package runeseg
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -138,3 +139,262 @@ func TestLineContextUnicodeTestCases(t *testing.T) {
 		t.Logf("Note: %d test cases still failing - this is expected during refactoring", failed)
 	}
 }
+
+// lineSegmentsOf returns the segments of the given string as returned by
+// FirstLineSegmentInString.
+func lineSegmentsOf(s string) []string {
+	var segments []string
+	state := -1
+	for len(s) > 0 {
+		var segment string
+		segment, s, _, state = FirstLineSegmentInString(s, state)
+		segments = append(segments, segment)
+	}
+	return segments
+}
+
+// TestLineContextCombiningMarkAtStart tests LB9 and LB10 for combining marks at
+// the start of a buffer, both when starting fresh and when resuming with the
+// state carried over from a previous buffer.
+func TestLineContextCombiningMarkAtStart(t *testing.T) {
+	// Fresh start: LB10 treats a leading combining mark as AL.
+	fresh := []struct {
+		input    string
+		expected []string
+	}{
+		{"\u0301b", []string{"\u0301b"}},
+		{"\u0301 b", []string{"\u0301 ", "b"}},
+		{"\u0301(b)", []string{"\u0301(b)"}}, // LB30: AL × OP.
+		{"\u0301\u0301b", []string{"\u0301\u0301b"}},
+	}
+	for _, tt := range fresh {
+		segments := lineSegmentsOf(tt.input)
+		if strings.Join(segments, "|") != strings.Join(tt.expected, "|") {
+			t.Errorf("%q: got segments %q, want %q", tt.input, segments, tt.expected)
+		}
+	}
+
+	// Carried state: the base character was in the previous buffer.
+	carried := []struct {
+		previous      string
+		next          string
+		expectedBreak []int // The break before each rune of "next".
+	}{
+		{"xa", "\u0301b", []int{LineDontBreak, LineDontBreak}}, // LB9, then LB28.
+		{"xa", "\u0301\u0301 b", []int{LineDontBreak, LineDontBreak, LineDontBreak, LineCanBreak}},
+		{"x ", "\u0301b", []int{LineCanBreak, LineDontBreak}},      // LB10 after a space.
+		{"x\n", "\u0301b", []int{LineMustBreak, LineDontBreak}},    // LB10 after a mandatory break.
+		{"x(", "\u0301b", []int{LineDontBreak, LineDontBreak}},     // LB9, then LB14.
+		{"👍\u200d", "\u0301b", []int{LineDontBreak, LineCanBreak}}, // LB8a, then LB31 (EB ÷ AL).
+	}
+	for _, tt := range carried {
+		state := -1
+		for _, r := range tt.previous {
+			state, _ = transitionLineBreakStateContext(state, r, nil, "")
+		}
+		runes := []rune(tt.next)
+		for i, r := range runes {
+			var lineBreak int
+			state, lineBreak = transitionLineBreakStateContext(state, r, nil, string(runes[i+1:]))
+			if lineBreak != tt.expectedBreak[i] {
+				t.Errorf("%q + %q: break before rune %d (%U) is %d, want %d", tt.previous, tt.next, i, r, lineBreak, tt.expectedBreak[i])
+			}
+		}
+	}
+
+	// Resuming a Step iteration in the middle of the text must not introduce a
+	// break before a combining mark.
+	str := "xa\u0301b"
+	state := -1
+	var c string
+	c, str, _, state = StepString(str, state)
+	if c != "x" {
+		t.Fatalf("Expected first cluster to be %q, got %q", "x", c)
+	}
+	var boundaries int
+	c, _, boundaries, _ = StepString(str, state)
+	if c != "a\u0301" || boundaries&MaskLine != LineDontBreak {
+		t.Errorf("Expected cluster %q with no line break, got %q with %d", "a\u0301", c, boundaries&MaskLine)
+	}
+}
+
+// TestLineContextOpenPunctuationSpaces tests LB14 (OP SP* ×) for runs of
+// spaces of different lengths, with and without combining marks.
+func TestLineContextOpenPunctuationSpaces(t *testing.T) {
+	for _, tt := range []struct {
+		input    string
+		expected []string
+	}{
+		{"(x", []string{"(x"}},
+		{"( x", []string{"( x"}},
+		{"(     x", []string{"(     x"}},
+		{"(\u0301x", []string{"(\u0301x"}},           // LB9: OP CM is OP.
+		{"(\u0301     x", []string{"(\u0301     x"}}, // LB9, then LB14.
+		{"(  \u0301x", []string{"(  \u0301x"}},       // LB10: CM after SP is AL, LB14 applies.
+		{"( \u0301 x", []string{"( \u0301 ", "x"}},   // The AL ends the OP SP* context.
+		{"a (     x) b", []string{"a ", "(     x) ", "b"}},
+		{"(     \u0301     x", []string{"(     \u0301     ", "x"}},
+	} {
+		segments := lineSegmentsOf(tt.input)
+		if strings.Join(segments, "|") != strings.Join(tt.expected, "|") {
+			t.Errorf("%q: got segments %q, want %q", tt.input, segments, tt.expected)
+		}
+	}
+
+	// The OP SP* context must survive spaces passed one Step at a time.
+	str := "(     x"
+	state := -1
+	for len(str) > 0 {
+		var (
+			c          string
+			boundaries int
+		)
+		c, str, boundaries, state = StepString(str, state)
+		if len(str) > 0 && boundaries&MaskLine != LineDontBreak {
+			t.Errorf("Unexpected line break after %q", c)
+		}
+	}
+}
+
+// TestLineContextNumericClose tests LB13 (× CL, × CP) and LB25 for closing
+// punctuation after numbers.
+func TestLineContextNumericClose(t *testing.T) {
+	for _, tt := range []struct {
+		input    string
+		expected []string
+	}{
+		{"(100)", []string{"(100)"}},
+		{"100)", []string{"100)"}},
+		{"[50]", []string{"[50]"}},
+		{"{3}", []string{"{3}"}},
+		{"(100)%", []string{"(100)%"}},       // LB25: NU (NU|SY|IS)* (CL|CP)? × PO.
+		{"(1.5)%", []string{"(1.5)%"}},       // With IS inside the number.
+		{"[100]$", []string{"[100]$"}},       // PR after the closing bracket.
+		{"(100) %", []string{"(100) ", "%"}}, // LB18: a space allows the break.
+		{"(a)%", []string{"(a)", "%"}},       // No numeric context.
+		{"a (100) b", []string{"a ", "(100) ", "b"}},
+	} {
+		segments := lineSegmentsOf(tt.input)
+		if strings.Join(segments, "|") != strings.Join(tt.expected, "|") {
+			t.Errorf("%q: got segments %q, want %q", tt.input, segments, tt.expected)
+		}
+	}
+}
+
+// TestLineContextHebrewHyphen tests LB21a (HL (HY|HH) × [^HL]): After a Hebrew
+// letter and a hyphen, a line may only be broken before another Hebrew letter.
+func TestLineContextHebrewHyphen(t *testing.T) {
+	for _, tt := range []struct {
+		input    string
+		expected []string
+	}{
+		{"\u05d0\u05d1-\u05d2\u05d3", []string{"\u05d0\u05d1-", "\u05d2\u05d3"}}, // HL HY ÷ HL.
+		{"\u05d0\u05be\u05d1", []string{"\u05d0\u05be", "\u05d1"}},               // HL HH (maqaf) ÷ HL.
+		{"\u05d0\u2010\u05d1", []string{"\u05d0\u2010", "\u05d1"}},               // HL HH ÷ HL.
+		{"\u05d5-Michael", []string{"\u05d5-Michael"}},                           // HL HY × AL.
+		{"\u05d5\u05be1", []string{"\u05d5\u05be1"}},                             // HL HH × NU.
+		{"\u05d0-\u0301b", []string{"\u05d0-\u0301b"}},                           // LB9: HY CM is HY.
+		{"\u05d0\u05b7-\u05d1", []string{"\u05d0\u05b7-", "\u05d1"}},             // LB9: HL CM is HL.
+		{"a-\u05d0", []string{"a-", "\u05d0"}},                                   // Not LB21a.
+		{"\u05d0\u00ad\u05d1", []string{"\u05d0\u00ad", "\u05d1"}},               // BA is not part of LB21a.
+	} {
+		segments := lineSegmentsOf(tt.input)
+		if strings.Join(segments, "|") != strings.Join(tt.expected, "|") {
+			t.Errorf("%q: got segments %q, want %q", tt.input, segments, tt.expected)
+		}
+	}
+}
+
+// TestLineInitialQuotationSot tests LB15.1 sot (QU_Pi SP*)+ × OP at the start
+// of text and after mandatory breaks, with both the original line breaking
+// rules and the context-based system.
+func TestLineInitialQuotationSot(t *testing.T) {
+	for _, tt := range []struct {
+		input    string
+		expected []string
+	}{
+		{"\u201c (foo)", []string{"\u201c (foo)"}},
+		{"\u00ab (foo)", []string{"\u00ab (foo)"}},
+		{"\u201c  (foo)", []string{"\u201c  (foo)"}},             // SP*
+		{"\u201c \u2018 (foo)", []string{"\u201c \u2018 (foo)"}}, // (QU_Pi SP*)+
+		{"x\n\u201c (foo)", []string{"x\n", "\u201c (foo)"}},
+		{"x\r\n\u201c (foo)", []string{"x\r\n", "\u201c (foo)"}},
+		{"x\u2028\u201c  (foo)", []string{"x\u2028", "\u201c  (foo)"}},
+		{"x \u201c (foo)", []string{"x ", "\u201c ", "(foo)"}}, // Not at sot.
+		{"x \u201c  (foo)", []string{"x ", "\u201c  ", "(foo)"}},
+		{"(\u201c (foo)", []string{"(\u201c ", "(foo)"}},
+	} {
+		// Context-based system.
+		segments := lineSegmentsOf(tt.input)
+
+		// Original rules, one code point at a time.
+		var oldSegments []string
+		runes := []rune(tt.input)
+		state := -1
+		for i, r := range runes {
+			var lineBreak int
+			state, lineBreak = transitionLineBreakState(state, r, nil, string(runes[i+1:]))
+			if i == 0 || lineBreak != LineDontBreak {
+				oldSegments = append(oldSegments, "")
+			}
+			oldSegments[len(oldSegments)-1] += string(r)
+		}
+
+		for _, result := range [][]string{segments, oldSegments} {
+			if strings.Join(result, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("%q: got segments %q, want %q", tt.input, result, tt.expected)
+			}
+		}
+	}
+}
+
+// TestLineContextRegionalIndicatorParity tests that any character other than a
+// regional indicator resets the RI pair count of LB30a, so that line breaks
+// between flags agree with the grapheme cluster boundaries of GB12 and GB13.
+func TestLineContextRegionalIndicatorParity(t *testing.T) {
+	for _, tt := range []struct {
+		input    string
+		expected []string
+	}{
+		{"a\U0001f1e9\U0001f1ea\U0001f1eb\U0001f1f7", []string{"a", "\U0001f1e9\U0001f1ea", "\U0001f1eb\U0001f1f7"}},
+		{"\U0001f1e9\U0001f1eax\U0001f1eb\U0001f1f7", []string{"\U0001f1e9\U0001f1ea", "x", "\U0001f1eb\U0001f1f7"}},
+		{"\U0001f1e9x\U0001f1eb\U0001f1f7", []string{"\U0001f1e9", "x", "\U0001f1eb\U0001f1f7"}}, // Odd count before the letter.
+		{"\U0001f1e9\U0001f1ea\U0001f1ebx\U0001f1ee\U0001f1f9\U0001f1ef", []string{"\U0001f1e9\U0001f1ea", "\U0001f1eb", "x", "\U0001f1ee\U0001f1f9", "\U0001f1ef"}},
+		{"\U0001f1e9\U0001f1ea \U0001f1eb\U0001f1f7", []string{"\U0001f1e9\U0001f1ea ", "\U0001f1eb\U0001f1f7"}},
+		{"\U0001f1e9 \U0001f1ea\U0001f1eb", []string{"\U0001f1e9 ", "\U0001f1ea\U0001f1eb"}},
+		{"\U0001f1e9\U0001f1ea\n\U0001f1eb\U0001f1f7\U0001f1ee", []string{"\U0001f1e9\U0001f1ea\n", "\U0001f1eb\U0001f1f7", "\U0001f1ee"}},
+	} {
+		segments := lineSegmentsOf(tt.input)
+
+		// Collect the segments via Step, which reports line breaks at
+		// grapheme cluster boundaries only.
+		var (
+			stepSegments []string
+			current      string
+		)
+		str, state := tt.input, -1
+		for len(str) > 0 {
+			var (
+				cluster    string
+				boundaries int
+			)
+			cluster, str, boundaries, state = StepString(str, state)
+			current += cluster
+			if boundaries&MaskLine != LineDontBreak {
+				stepSegments = append(stepSegments, current)
+				current = ""
+			}
+		}
+
+		for _, result := range [][]string{segments, stepSegments} {
+			if strings.Join(result, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("%q: got segments %q, want %q", tt.input, result, tt.expected)
+			}
+		}
+		for _, seg := range tt.expected {
+			if n := GraphemeClusterCount(strings.TrimRight(seg, " \n")); n != 1 {
+				t.Errorf("%q: segment %q has %d grapheme clusters, expected 1", tt.input, seg, n)
+			}
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package runeseg
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// LineBreakOptions specifies tailorings of the line breaking algorithm of
+// [Unicode Standard Annex #14]. The zero value applies the algorithm without
+// any tailoring, i.e. its methods then return the same results as the
+// corresponding package-level functions.
+//
+// [Unicode Standard Annex #14]: https://www.unicode.org/reports/tr14/
+type LineBreakOptions struct {
+	// BreakBeforeSpaces determines where the break opportunity around a run of
+	// breakable spaces is reported. UAX #14 places it after the spaces (LB18),
+	// so that spaces trail the preceding segment:
+	//
+	//	"First |line."
+	//
+	// This is what word processors typically do because trailing spaces are
+	// hidden at the end of a line. If BreakBeforeSpaces is set, the
+	// opportunity is reported before the spaces instead, so that they lead
+	// the following segment:
+	//
+	//	"First| line."
+	//
+	// This is useful for terminal emulators and right-aligned text where
+	// trailing spaces should not be counted towards the width of a line. The
+	// opportunity is only moved if the algorithm allows a break after the
+	// spaces. Spaces at the end of the text or before a mandatory break stay
+	// where they are.
+	//
+	// Note that the entire space run must be contained in the byte slice or
+	// string passed to [LineBreakOptions.Step] or
+	// [LineBreakOptions.StepString] for the opportunity to be moved.
+	BreakBeforeSpaces bool
+
+	// KeepBrackets suppresses all break opportunities inside bracketed runs
+	// such as "[a b c]" or "{x y}", which is useful for code snippets and
+	// mathematical expressions. A run starts with an opening bracket (class
+	// OP) and ends with the closing bracket (class CL or CP) at the same
+	// nesting depth. The type of bracket is not considered. Mandatory breaks
+	// inside a run are not affected, neither are break opportunities before
+	// or after it.
+	//
+	// Opening brackets without a closing bracket in the remaining text are
+	// ignored, i.e. the normal rules apply after them. Hence, the entire run
+	// must be contained in the byte slice or string passed to
+	// [LineBreakOptions.Step] or [LineBreakOptions.StepString]. Nesting depths
+	// beyond 255 are not tracked.
+	//
+	// The closing bracket is only searched for within the 256 characters
+	// following an opening bracket at depth 0. Longer runs are treated like
+	// unmatched brackets. This keeps the cost linear in the length of the
+	// text even if it contains many unmatched opening brackets, such as
+	// emoticons like ":(" in chat logs.
+	KeepBrackets bool
+
+	// NoBreakBefore and NoBreakAfter contain characters which may not start
+	// and end a line, respectively, such as the kinsoku shori rules of
+	// Japanese typesetting. No break opportunity is reported before a
+	// grapheme cluster beginning with a character from NoBreakBefore or after
+	// a grapheme cluster ending with a character from NoBreakAfter. Break
+	// opportunities are only removed, never added, and mandatory breaks are
+	// not affected.
+	//
+	// UAX #14 already implements most of the common kinsoku rules: There is
+	// no break before small kana, iteration marks, closing brackets, and
+	// punctuation such as U+3002 IDEOGRAPHIC FULL STOP, and none after opening
+	// brackets. Breaks on both sides of ideographs are allowed. These options
+	// are intended for the remaining cases and for other conventions, e.g.:
+	//
+	//	LineBreakOptions{
+	//		NoBreakBefore: "\u3033\u3034",       // Vertical kana repeat marks.
+	//		NoBreakAfter:  "\u3012\uff03\uff20", // Postal mark, fullwidth "#" and "@".
+	//	}
+	NoBreakBefore, NoBreakAfter string
+}
+
+// The bracket nesting depth tracked for the KeepBrackets option is stored in
+// the upper bits of the state, above the bits used by [Step]. Like those, they
+// are only available where int has 64 bits.
+const (
+	shiftBracketDepth = 48
+	maskBracketDepth  = 0xff
+)
+
+// maxBracketRun is the maximum number of runes searched for the closing
+// bracket of a run for the KeepBrackets option.
+const maxBracketRun = 256
+
+// Step is like [Step] but applies the tailorings specified in the options to
+// the line break information in the returned boundaries.
+func (o LineBreakOptions) Step(b []byte, state int) (cluster, rest []byte, boundaries int, newState int) {
+	var depth int
+	if state >= 0 {
+		// Only variables are shifted by the constant amount so that this
+		// compiles where int has 32 bits.
+		upper := state >> shiftBracketDepth
+		depth = upper & maskBracketDepth
+		state -= upper << shiftBracketDepth
+	}
+	cluster, rest, boundaries, newState = Step(b, state)
+	if len(rest) > 0 {
+		r, _ := utf8.DecodeLastRune(cluster)
+		lineBreak := o.tailorLineBreak(boundaries&MaskLine, r, rest, "", newState)
+		if o.KeepBrackets {
+			depth = bracketDepth(depth, b, "", len(cluster))
+			if depth > 0 && lineBreak == LineCanBreak {
+				lineBreak = LineDontBreak
+			}
+			newState |= depth << shiftBracketDepth
+		}
+		boundaries = boundaries&^MaskLine | lineBreak
+	}
+	return
+}
+
+// StepString is like [StepString] but applies the tailorings specified in the
+// options to the line break information in the returned boundaries.
+func (o LineBreakOptions) StepString(str string, state int) (cluster, rest string, boundaries int, newState int) {
+	var depth int
+	if state >= 0 {
+		// Only variables are shifted by the constant amount so that this
+		// compiles where int has 32 bits.
+		upper := state >> shiftBracketDepth
+		depth = upper & maskBracketDepth
+		state -= upper << shiftBracketDepth
+	}
+	cluster, rest, boundaries, newState = StepString(str, state)
+	if len(rest) > 0 {
+		r, _ := utf8.DecodeLastRuneInString(cluster)
+		lineBreak := o.tailorLineBreak(boundaries&MaskLine, r, nil, rest, newState)
+		if o.KeepBrackets {
+			depth = bracketDepth(depth, nil, str, len(cluster))
+			if depth > 0 && lineBreak == LineCanBreak {
+				lineBreak = LineDontBreak
+			}
+			newState |= depth << shiftBracketDepth
+		}
+		boundaries = boundaries&^MaskLine | lineBreak
+	}
+	return
+}
+
+// tailorLineBreak applies the options to the line break decision "lineBreak"
+// between a grapheme cluster ending in rune "last" and the text following it
+// (either the byte slice "b" or the string "str", whichever is not nil or
+// empty). The state is the [Step] state after the boundary.
+func (o LineBreakOptions) tailorLineBreak(lineBreak int, last rune, b []byte, str string, state int) int {
+	if o.BreakBeforeSpaces {
+		lineBreak = breakBeforeSpaces(lineBreak, last, b, str, state)
+	}
+	if lineBreak == LineCanBreak && (o.NoBreakBefore != "" || o.NoBreakAfter != "") {
+		var next rune
+		if b != nil {
+			next, _ = utf8.DecodeRune(b)
+		} else {
+			next, _ = utf8.DecodeRuneInString(str)
+		}
+		if strings.ContainsRune(o.NoBreakAfter, last) || strings.ContainsRune(o.NoBreakBefore, next) {
+			return LineDontBreak
+		}
+	}
+	return lineBreak
+}
+
+// breakBeforeSpaces moves the line break opportunity after a run of spaces to
+// the start of the run, for the BreakBeforeSpaces option. The arguments are
+// the same as for [LineBreakOptions.tailorLineBreak].
+func breakBeforeSpaces(lineBreak int, last rune, b []byte, str string, state int) int {
+	lastProp, _ := propertyLineBreak(last)
+	if lineBreak == LineCanBreak && lastProp == prSP {
+		// The opportunity after a run of spaces was already reported before
+		// the run.
+		return LineDontBreak
+	} else if lineBreak == LineDontBreak && lastProp != prSP {
+		// Report the opportunity after a run of spaces starting here before
+		// the run.
+		var (
+			r rune
+			l int
+		)
+		if b != nil {
+			r, l = utf8.DecodeRune(b)
+			b = b[l:]
+		} else {
+			r, l = utf8.DecodeRuneInString(str)
+			str = str[l:]
+		}
+		if prop, _ := propertyLineBreak(r); prop == prSP {
+			lineState := (state >> shiftLineState) & maskLineState
+			if lineBreakAfterSpaces(lineState, b, str) == LineCanBreak {
+				return LineCanBreak
+			}
+		}
+	}
+	return lineBreak
+}
+
+// lineBreakAfterSpaces returns the line break decision at the end of a run of
+// spaces, given the line break state after the first space of the run and the
+// text following that space (either the byte slice "b" or the string "str",
+// whichever is not nil or empty). LineDontBreak is returned if the run extends
+// to the end of the text or if the run's last space is extended by a combining
+// mark, i.e. if there is no grapheme cluster boundary at the end of the run.
+func lineBreakAfterSpaces(lineState int, b []byte, str string) int {
+	for {
+		var (
+			r rune
+			l int
+		)
+		if b != nil {
+			r, l = utf8.DecodeRune(b)
+			b = b[l:]
+		} else {
+			r, l = utf8.DecodeRuneInString(str)
+			str = str[l:]
+		}
+		if l == 0 {
+			return LineDontBreak // End of text.
+		}
+		var lineBreak int
+		lineState, lineBreak = transitionLineBreakStateContext(lineState, r, b, str)
+		if prop, _ := propertyLineBreak(r); prop == prSP {
+			continue
+		}
+		switch propertyGraphemes(r) {
+		case prExtend, prZWJ, prSpacingMark:
+			return LineDontBreak // No grapheme cluster boundary here.
+		}
+		return lineBreak
+	}
+}
+
+// bracketDepth returns the bracket nesting depth after the grapheme cluster of
+// the given length at the start of the byte slice "b" or the string "str"
+// (whichever is not nil or empty), given the depth before the cluster. An
+// opening bracket at depth 0 only increases the depth if it has a matching
+// closing bracket in the text.
+func bracketDepth(depth int, b []byte, str string, clusterLength int) int {
+	for pos := 0; pos < clusterLength; {
+		var (
+			r rune
+			l int
+		)
+		if b != nil {
+			r, l = utf8.DecodeRune(b[pos:])
+		} else {
+			r, l = utf8.DecodeRuneInString(str[pos:])
+		}
+		pos += l
+		switch prop, _ := propertyLineBreak(r); prop {
+		case prOP:
+			if depth == 0 && !hasClosingBracket(b, str, pos) || depth >= maskBracketDepth {
+				continue
+			}
+			depth++
+		case prCL, prCP:
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+	return depth
+}
+
+// hasClosingBracket returns true if the byte slice "b" or the string "str"
+// (whichever is not nil or empty), starting at byte position "pos" right after
+// an opening bracket, contains the closing bracket matching that opening
+// bracket within the next maxBracketRun runes.
+func hasClosingBracket(b []byte, str string, pos int) bool {
+	depth := 1
+	for runes := 0; runes < maxBracketRun; runes++ {
+		var (
+			r rune
+			l int
+		)
+		if b != nil {
+			r, l = utf8.DecodeRune(b[pos:])
+		} else {
+			r, l = utf8.DecodeRuneInString(str[pos:])
+		}
+		if l == 0 {
+			return false // End of text.
+		}
+		pos += l
+		switch prop, _ := propertyLineBreak(r); prop {
+		case prOP:
+			depth++
+		case prCL, prCP:
+			depth--
+			if depth == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package runeseg
+
+import (
+	"strings"
+	"testing"
+)
+
+// lineOptionsSegments returns the given string with "|" inserted at every
+// optional line break and "‖" at every mandatory line break, as determined by
+// [LineBreakOptions.StepString] and [LineBreakOptions.Step].
+func lineOptionsSegments(t *testing.T, options LineBreakOptions, text string) string {
+	t.Helper()
+	var (
+		str, bytesResult strings.Builder
+		c                string
+		boundaries       int
+	)
+	state := -1
+	rest := text
+	for len(rest) > 0 {
+		c, rest, boundaries, state = options.StepString(rest, state)
+		str.WriteString(c)
+		switch boundaries & MaskLine {
+		case LineCanBreak:
+			str.WriteString("|")
+		case LineMustBreak:
+			str.WriteString("‖")
+		}
+	}
+
+	// The byte slice version must yield the same result.
+	var cb []byte
+	state = -1
+	b := []byte(text)
+	for len(b) > 0 {
+		cb, b, boundaries, state = options.Step(b, state)
+		bytesResult.Write(cb)
+		switch boundaries & MaskLine {
+		case LineCanBreak:
+			bytesResult.WriteString("|")
+		case LineMustBreak:
+			bytesResult.WriteString("‖")
+		}
+	}
+	if str.String() != bytesResult.String() {
+		t.Errorf("Step and StepString differ for %q: %q vs. %q", text, bytesResult.String(), str.String())
+	}
+
+	return str.String()
+}
+
+// Test the BreakBeforeSpaces line break option.
+func TestLineBreakOptionsBreakBeforeSpaces(t *testing.T) {
+	for _, testCase := range []struct {
+		original         string
+		trailing, before string
+	}{
+		{"First line.", "First |line.‖", "First| line.‖"},
+		{"a   b", "a   |b‖", "a|   b‖"},
+		{"one two three", "one |two |three‖", "one| two| three‖"},
+		{"  a b", "  |a |b‖", "  a| b‖"},             // Leading spaces.
+		{"a b  ", "a |b  ‖", "a| b  ‖"},              // Trailing spaces.
+		{"a \nb", "a \n‖b‖", "a \n‖b‖"},              // Spaces before a mandatory break.
+		{"( x", "( x‖", "( x‖"},                      // LB14 forbids the break.
+		{"a  \u0301b", "a  \u0301b‖", "a  \u0301b‖"}, // No cluster boundary after the spaces.
+		{"世 界", "世 |界‖", "世| 界‖"},
+		{"First line.\nSecond line.", "First |line.\n‖Second |line.‖", "First| line.\n‖Second| line.‖"},
+	} {
+		if actual := lineOptionsSegments(t, LineBreakOptions{}, testCase.original); actual != testCase.trailing {
+			t.Errorf("Trailing spaces: %q yields %q, expected %q", testCase.original, actual, testCase.trailing)
+		}
+		if actual := lineOptionsSegments(t, LineBreakOptions{BreakBeforeSpaces: true}, testCase.original); actual != testCase.before {
+			t.Errorf("Leading spaces: %q yields %q, expected %q", testCase.original, actual, testCase.before)
+		}
+	}
+}
+
+// Test the KeepBrackets line break option.
+func TestLineBreakOptionsKeepBrackets(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		normal   string
+		kept     string
+	}{
+		{"a [b c] d", "a |[b |c] |d‖", "a |[b c] |d‖"},
+		{"{x y}", "{x |y}‖", "{x y}‖"},
+		{"f(a, b) + g(c, d)", "f(a, |b) |+ |g(c, |d)‖", "f(a, b) |+ |g(c, d)‖"},
+		{"[a (b c) d] e", "[a |(b |c) |d] |e‖", "[a (b c) d] |e‖"}, // Nested.
+		{"[a (b c] d", "[a |(b |c] |d‖", "[a |(b c] |d‖"},          // Nesting depth decides, not the type.
+		{"[a b c", "[a |b |c‖", "[a |b |c‖"},                       // Unmatched.
+		{"x [a b c", "x |[a |b |c‖", "x |[a |b |c‖"},               // Unmatched.
+		{"[a [b c] d", "[a |[b |c] |d‖", "[a |[b c] |d‖"},          // Unmatched outer bracket.
+		{"[a\nb] c", "[a\n‖b] |c‖", "[a\n‖b] |c‖"},                 // Mandatory breaks are kept.
+		{"¿Qué pasa?", "¿Qué |pasa?‖", "¿Qué |pasa?‖"},             // Not a bracket pair.
+		{"「日本」語", "「日|本」|語‖", "「日本」|語‖"},
+	} {
+		if actual := lineOptionsSegments(t, LineBreakOptions{}, testCase.original); actual != testCase.normal {
+			t.Errorf("Without KeepBrackets: %q yields %q, expected %q", testCase.original, actual, testCase.normal)
+		}
+		if actual := lineOptionsSegments(t, LineBreakOptions{KeepBrackets: true}, testCase.original); actual != testCase.kept {
+			t.Errorf("With KeepBrackets: %q yields %q, expected %q", testCase.original, actual, testCase.kept)
+		}
+	}
+
+	// Combined with BreakBeforeSpaces.
+	options := LineBreakOptions{KeepBrackets: true, BreakBeforeSpaces: true}
+	if actual, expected := lineOptionsSegments(t, options, "a [b c] d"), "a| [b c]| d‖"; actual != expected {
+		t.Errorf("With KeepBrackets and BreakBeforeSpaces: got %q, expected %q", actual, expected)
+	}
+
+	// Runs longer than the search limit and many unmatched brackets (which
+	// must not take quadratic time) are handled like unmatched brackets.
+	for _, text := range []string{
+		"[" + strings.Repeat("a ", maxBracketRun) + "]",
+		strings.Repeat(":( ", 20000),
+	} {
+		expected := lineOptionsSegments(t, LineBreakOptions{}, text)
+		if actual := lineOptionsSegments(t, LineBreakOptions{KeepBrackets: true}, text); actual != expected {
+			t.Errorf("With KeepBrackets: %.10q... yields %.20q..., expected %.20q...", text, actual, expected)
+		}
+	}
+}
+
+// Test the NoBreakBefore and NoBreakAfter line break options.
+func TestLineBreakOptionsKinsoku(t *testing.T) {
+	options := LineBreakOptions{
+		NoBreakBefore: "〳〴",
+		NoBreakAfter:  "〒＃＠",
+	}
+	for _, testCase := range []struct {
+		original string
+		normal   string
+		kinsoku  string
+	}{
+		{"日本語", "日|本|語‖", "日|本|語‖"}, // Ideographs are not restricted.
+		{"日〳本", "日|〳|本‖", "日〳|本‖"},
+		{"日＃本", "日|＃|本‖", "日|＃本‖"},
+		{"〒100 東京", "〒|100 |東|京‖", "〒100 |東|京‖"},
+		{"日〴＠本", "日|〴|＠|本‖", "日〴|＠本‖"},
+		{"a 〳", "a |〳‖", "a 〳‖"},
+		{"日\n〳", "日\n‖〳‖", "日\n‖〳‖"}, // Mandatory breaks are kept.
+		{"＃\n日", "＃\n‖日‖", "＃\n‖日‖"},
+		{"「日本」", "「日|本」‖", "「日|本」‖"}, // UAX #14 handles brackets.
+	} {
+		if actual := lineOptionsSegments(t, LineBreakOptions{}, testCase.original); actual != testCase.normal {
+			t.Errorf("Without kinsoku: %q yields %q, expected %q", testCase.original, actual, testCase.normal)
+		}
+		if actual := lineOptionsSegments(t, options, testCase.original); actual != testCase.kinsoku {
+			t.Errorf("With kinsoku: %q yields %q, expected %q", testCase.original, actual, testCase.kinsoku)
+		}
+	}
+
+	// Combined with KeepBrackets.
+	options.KeepBrackets = true
+	if actual, expected := lineOptionsSegments(t, options, "〒(日 本)語"), "〒(日 本)|語‖"; actual != expected {
+		t.Errorf("With KeepBrackets: got %q, expected %q", actual, expected)
+	}
+}
@@ -3665,3 +3665,11 @@ var lineBreakCodePoints = [][4]int{
 	{0xF0000, 0xFFFFD, prXX, gcCo},   // [65534] <private-use-F0000>..<private-use-FFFFD>
 	{0x100000, 0x10FFFD, prXX, gcCo}, // [65534] <private-use-100000>..<private-use-10FFFD>
 }
+
+// lineBreakCodePointsVersion is the Unicode version of the data file
+// lineBreakCodePoints was generated from.
+// lineBreakCodePointsDate is the date of the generation.
+const (
+	lineBreakCodePointsVersion = "17.0.0"
+	lineBreakCodePointsDate    = "2026-01-05"
+)
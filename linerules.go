@@ -277,9 +277,9 @@ func lbTransitions(state, prop int) (newState, lineBreak, rule int) {
 	case lbB2SP | prAny<<32:
 		return lbAny, LineCanBreak, 180
 	// LB15.1: sot (QU_Pi SP*)+ × OP
-	// This rule ONLY applies at the start of text (after sot or mandatory break)
-	// For now, we don't track "sot" state, so we apply LB18 (break after SP)
-	// and rely on the test cases to verify correctness
+	// This rule ONLY applies at the start of text (after sot or mandatory break).
+	// The sot context is tracked with lbSotBit in transitionLineBreakState, so
+	// the table applies LB18 (break after SP) for all other cases.
 	case lbQUPiSP | prAny<<32:
 		return lbAny, LineCanBreak, 180 // LB18 applies
 	case lbQUPiSP | prOP<<32:
@@ -675,6 +675,10 @@ func transitionLineBreakState(state int, r rune, b []byte, str string) (newState
 	if isSot && state == lbQUPiSP && rule >= 180 {
 		// At sot with QU_Pi SP, don't break before most characters
 		// This prevents breaks after initial quotation + space at start of text
+		if nextProperty == prQU && generalCategory == gcPi && newState == lbQU {
+			// Another initial quotation mark: (QU_Pi SP*)+
+			return lbQUPi | lbSotBit, LineDontBreak
+		}
 		return newState, LineDontBreak
 	}
 
@@ -736,10 +740,11 @@ func transitionLineBreakState(state int, r rune, b []byte, str string) (newState
 	}
 
 	// Track QU_Pi specially for LB15.1
-	// Also carry the sot bit through for LB15.1 context
+	// Also carry the sot bit through for LB15.1 context. A mandatory break
+	// starts a new line, so a QU_Pi following it is also at sot.
 	if nextProperty == prQU && generalCategory == gcPi && newState == lbQU {
 		newState = lbQUPi
-		if isSot {
+		if isSot || state == lbBK || state == lbCR || state == lbLF || state == lbNL {
 			newState |= lbSotBit // Carry sot bit for LB15.1
 		}
 	}
@@ -749,10 +754,13 @@ func transitionLineBreakState(state int, r rune, b []byte, str string) (newState
 		newState |= lbSotBit
 	}
 
-	// Carry sot bit through QU_Pi -> SP transition
+	// Carry sot bit through QU_Pi -> SP transition and any further spaces (SP*)
 	if isSot && state == lbQUPi && newState == lbQUPiSP {
 		newState |= lbSotBit
 	}
+	if isSot && state == lbQUPiSP && nextProperty == prSP {
+		newState = lbQUPiSP | lbSotBit
+	}
 
 	// LB25 (look ahead).
 	if rule > 250 &&
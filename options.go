@@ -0,0 +1,333 @@
+package runeseg
+
+import "unicode/utf8"
+
+// Options specifies tailorings of the boundaries and widths returned by
+// [StepWithOptions] and [StepStringWithOptions]. The zero value applies no
+// tailoring, i.e. these functions then return the same results as [Step] and
+// [StepString].
+type Options struct {
+	// AmbiguousWidth is the monospace width of East Asian characters
+	// classified as Ambiguous. If it is 0, [EastAsianAmbiguousWidth] is used.
+	// Unlike that package variable, this setting only affects the calls it is
+	// passed to.
+	AmbiguousWidth int
+
+	// TabWidth is the monospace width of a horizontal tab (U+0009). If it is
+	// 0, tabs have a width of 0 like all other control characters. Tab stops
+	// are not taken into account, i.e. every tab has the same width. See
+	// TabStop for functions which expand tabs.
+	TabWidth int
+
+	// TabStop is the distance between tab stops. If it is positive, a
+	// horizontal tab advances to the next multiple of TabStop, i.e. its width
+	// depends on the column at which it starts. This is only applied by
+	// functions which know the column: [Options.StringWidth],
+	// [Options.MaxLineWidth], [NextLineBreakWithin], and [WordWrapper]. They
+	// start counting columns at the beginning of the text (for a WordWrapper,
+	// at the beginning of a line's indentation) and again after every
+	// mandatory line break. [StepWithOptions] and [StepStringWithOptions]
+	// process one grapheme cluster at a time and report tabs with TabWidth.
+	TabStop int
+
+	// ParagraphBreaks changes the treatment of line breaks for sentence
+	// boundaries. UAX #29 ends a sentence at every line break (SB4) which is
+	// not what is wanted for hard-wrapped text such as e-mails or source code
+	// comments, where a sentence often spans several lines. If this flag is
+	// set, a line break (CR, LF, CR LF, NEL, or U+2028) which follows a line
+	// with unterminated text is not a sentence boundary. Sentences then only
+	// end with sentence terminators, paragraph separators (U+2029), and empty
+	// lines:
+	//
+	//	"This sentence\nspans two lines.\nThis one doesn't."
+	//
+	// has the two sentences "This sentence\nspans two lines.\n" and
+	// "This one doesn't.". Word boundaries and line breaks are not affected.
+	ParagraphBreaks bool
+
+	// NumberedLists suppresses the sentence boundary after a number followed
+	// by a full stop at the start of a sentence, which is usually the marker of
+	// an ordered list item rather than a complete sentence:
+	//
+	//	"1. First item. 2. Second item."
+	//
+	// has the two sentences "1. First item. " and "2. Second item." instead of
+	// the four returned by UAX #29. Numbers in the middle of a sentence are
+	// not affected because they are inherently ambiguous: "See item 2. Then
+	// go." contains two sentences, just like "First item 2. Second item".
+	NumberedLists bool
+}
+
+// The flag tracked for the ParagraphBreaks option, indicating that the
+// previous grapheme cluster was part of an unterminated line, is stored in the
+// upper bits of the state, above the bits used by [Step] and
+// [LineBreakOptions]. It is followed by the two bits of the list marker state
+// tracked for the NumberedLists option. Like the upper bits used by [Step],
+// these bits are only available where int has 64 bits.
+const (
+	shiftOpenLine   = 56
+	shiftListMarker = 57
+)
+
+// The states of the list marker recognition for the NumberedLists option.
+const (
+	listStart  = iota // At the start of a sentence.
+	listDigits        // After digits at the start of a sentence.
+	listTerm          // After digits and a full stop, and possibly spaces.
+	listNone          // Not a list marker.
+)
+
+// StepWithOptions is like [Step] but applies the tailorings specified in the
+// options to the returned boundaries. StepWithOptions(b, state, Options{})
+// returns the same results as Step(b, state). The state must have been
+// returned by a previous call to StepWithOptions with the same options, or
+// be -1.
+func StepWithOptions(b []byte, state int, opts Options) (cluster, rest []byte, boundaries int, newState int) {
+	var (
+		openLine          bool
+		listMarker, flags int
+	)
+	if state >= 0 {
+		// Only variables are shifted by the constant amounts so that this
+		// compiles where int has 32 bits.
+		upper := state >> shiftOpenLine
+		openLine = upper&1 != 0
+		listMarker = upper >> (shiftListMarker - shiftOpenLine) & 3
+		state -= upper << shiftOpenLine
+	}
+	cluster, rest, boundaries, newState = Step(b, state)
+	if len(cluster) == 0 || opts == (Options{}) {
+		return
+	}
+	r, length := utf8.DecodeRune(cluster)
+	boundaries = opts.tailorWidth(boundaries, r, cluster, "")
+	if opts.ParagraphBreaks {
+		sentenceState := (state >> shiftSentenceState) & maskSentenceState
+		if state < 0 {
+			sentenceState, _ = transitionSentenceBreakState(-1, r, b[length:], "")
+		}
+		boundaries = tailorSentenceBreak(boundaries, r, openLine, len(rest) > 0)
+		if isOpenLineState(sentenceState) {
+			flags |= 1
+		}
+	}
+	if opts.NumberedLists {
+		boundaries, listMarker = tailorListMarker(boundaries, r, listMarker, len(rest) > 0)
+		flags |= listMarker << (shiftListMarker - shiftOpenLine)
+	}
+	newState |= flags << shiftOpenLine
+	return
+}
+
+// StepStringWithOptions is like [StepWithOptions] but its input and outputs
+// are strings.
+func StepStringWithOptions(str string, state int, opts Options) (cluster, rest string, boundaries int, newState int) {
+	var (
+		openLine          bool
+		listMarker, flags int
+	)
+	if state >= 0 {
+		// Only variables are shifted by the constant amounts so that this
+		// compiles where int has 32 bits.
+		upper := state >> shiftOpenLine
+		openLine = upper&1 != 0
+		listMarker = upper >> (shiftListMarker - shiftOpenLine) & 3
+		state -= upper << shiftOpenLine
+	}
+	cluster, rest, boundaries, newState = StepString(str, state)
+	if len(cluster) == 0 || opts == (Options{}) {
+		return
+	}
+	r, length := utf8.DecodeRuneInString(cluster)
+	boundaries = opts.tailorWidth(boundaries, r, nil, cluster)
+	if opts.ParagraphBreaks {
+		sentenceState := (state >> shiftSentenceState) & maskSentenceState
+		if state < 0 {
+			sentenceState, _ = transitionSentenceBreakState(-1, r, nil, str[length:])
+		}
+		boundaries = tailorSentenceBreak(boundaries, r, openLine, len(rest) > 0)
+		if isOpenLineState(sentenceState) {
+			flags |= 1
+		}
+	}
+	if opts.NumberedLists {
+		boundaries, listMarker = tailorListMarker(boundaries, r, listMarker, len(rest) > 0)
+		flags |= listMarker << (shiftListMarker - shiftOpenLine)
+	}
+	newState |= flags << shiftOpenLine
+	return
+}
+
+// StringWidth is like [StringWidth] but applies the width options, i.e. it
+// returns the sum of the widths reported by [StepStringWithOptions], with tabs
+// expanded to the next tab stop if TabStop is set.
+func (o Options) StringWidth(s string) (width int) {
+	var (
+		cluster            string
+		boundaries, column int
+	)
+	state := -1
+	for len(s) > 0 {
+		cluster, s, boundaries, state = StepStringWithOptions(s, state, o)
+		w := o.columnWidth(cluster, boundaries>>ShiftWidth, column)
+		width += w
+		column += w
+		if boundaries&MaskLine == LineMustBreak {
+			column = 0
+		}
+	}
+	return
+}
+
+// MaxLineWidth is like [MaxLineWidth] but applies the width options, as
+// [Options.StringWidth] does. Tabs are expanded relative to the start of each
+// line.
+func (o Options) MaxLineWidth(s string) (maxWidth int) {
+	var (
+		cluster           string
+		boundaries, width int
+	)
+	state := -1
+	for len(s) > 0 {
+		cluster, s, boundaries, state = StepStringWithOptions(s, state, o)
+		width += o.columnWidth(cluster, boundaries>>ShiftWidth, width)
+		if boundaries&MaskLine == LineMustBreak {
+			if width > maxWidth {
+				maxWidth = width
+			}
+			width = 0
+		}
+	}
+	return
+}
+
+// Fits is like [Fits] but applies the width options, as
+// [Options.MaxLineWidth] does.
+func (o Options) Fits(s string, width int) bool {
+	var (
+		cluster               string
+		boundaries, lineWidth int
+	)
+	state := -1
+	for len(s) > 0 {
+		cluster, s, boundaries, state = StepStringWithOptions(s, state, o)
+		lineWidth += o.columnWidth(cluster, boundaries>>ShiftWidth, lineWidth)
+		if lineWidth > width {
+			return false
+		}
+		if boundaries&MaskLine == LineMustBreak {
+			lineWidth = 0
+		}
+	}
+	return true
+}
+
+// columnWidth returns the width of the given grapheme cluster when it starts
+// at the given column, given the width reported by [StepStringWithOptions].
+// This is the distance to the next tab stop for tabs if TabStop is set, and
+// the reported width otherwise.
+func (o Options) columnWidth(cluster string, width, column int) int {
+	if o.TabStop > 0 && cluster == "\t" {
+		return o.TabStop - column%o.TabStop
+	}
+	return width
+}
+
+// tailorWidth applies the width options to the boundaries of the grapheme
+// cluster starting with rune "r", given as the byte slice "b" or the string
+// "str" (whichever is not nil or empty).
+func (o Options) tailorWidth(boundaries int, r rune, b []byte, str string) int {
+	width := boundaries >> ShiftWidth
+	if r == '\t' && o.TabWidth != 0 {
+		width = o.TabWidth
+	} else if o.AmbiguousWidth != 0 && o.AmbiguousWidth != EastAsianAmbiguousWidth {
+		width += ambiguousRunes(b, str) * (o.AmbiguousWidth - EastAsianAmbiguousWidth)
+	}
+	return boundaries&(1<<ShiftWidth-1) | width<<ShiftWidth
+}
+
+// ambiguousRunes returns the number of runes in the grapheme cluster given as
+// the byte slice "b" or the string "str" (whichever is not nil or empty) which
+// contribute a width of [EastAsianAmbiguousWidth] to the cluster's width.
+func ambiguousRunes(b []byte, str string) (n int) {
+	var firstProp int
+	for pos := 0; ; {
+		var (
+			r rune
+			l int
+		)
+		if b != nil {
+			r, l = utf8.DecodeRune(b[pos:])
+		} else {
+			r, l = utf8.DecodeRuneInString(str[pos:])
+		}
+		if l == 0 {
+			return
+		}
+		prop := propertyGraphemes(r)
+		if pos == 0 {
+			firstProp = prop
+		} else if firstProp == prExtendedPictographic || firstProp == prRegionalIndicator || firstProp == prL {
+			return // Only the first rune determines the width (see runeWidth).
+		}
+		pos += l
+		switch prop {
+		case prControl, prCR, prLF, prExtend, prZWJ, prRegionalIndicator, prExtendedPictographic:
+			continue
+		}
+		if r == 0x2e3a || r == 0x2e3b || r == utf8.RuneError && l == 1 {
+			continue
+		}
+		if propertyEastAsianWidth(r) == prA {
+			n++
+		}
+	}
+}
+
+// tailorSentenceBreak removes the sentence boundary after a grapheme cluster
+// starting with rune "r" if it is a line break within a paragraph, i.e. if it
+// follows an unterminated line ("openLine") and is not at the end of the text.
+func tailorSentenceBreak(boundaries int, r rune, openLine, hasRest bool) int {
+	if !openLine || !hasRest || r == 0x2029 {
+		return boundaries
+	}
+	switch property(sentenceBreakCodePoints, r) {
+	case prCR, prLF, prSep:
+		return boundaries &^ MaskSentence
+	}
+	return boundaries
+}
+
+// tailorListMarker removes the sentence boundary after a grapheme cluster
+// starting with rune "r" if it ends a list marker, i.e. a number and a full
+// stop at the start of a sentence, and it is not at the end of the text. It
+// returns the new boundaries and the list marker state following the cluster.
+func tailorListMarker(boundaries int, r rune, listMarker int, hasRest bool) (int, int) {
+	prop := property(sentenceBreakCodePoints, r)
+	switch {
+	case prop == prNumeric && (listMarker == listStart || listMarker == listDigits):
+		listMarker = listDigits
+	case prop == prATerm && listMarker == listDigits:
+		listMarker = listTerm
+	case prop == prSp && listMarker == listTerm:
+	default:
+		listMarker = listNone
+	}
+	if boundaries&MaskSentence == 0 {
+		return boundaries, listMarker
+	}
+	if listMarker == listTerm && hasRest {
+		return boundaries &^ MaskSentence, listNone
+	}
+	return boundaries, listStart
+}
+
+// isOpenLineState returns true if the given sentence break state indicates
+// text which is not terminated by a sentence terminator or a line break.
+func isOpenLineState(sentenceState int) bool {
+	switch sentenceState {
+	case sbAny, sbUpper, sbLower:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,316 @@
+package runeseg
+
+import (
+	"strings"
+	"testing"
+)
+
+// stepWithOptions splits the given string with StepStringWithOptions and
+// StepWithOptions, returning the clusters and the boundaries of each. It fails
+// the test if the two functions disagree.
+func stepWithOptions(t *testing.T, str string, opts Options) (clusters []string, boundaries []int) {
+	t.Helper()
+	state := -1
+	for s := str; len(s) > 0; {
+		var (
+			cluster  string
+			boundary int
+		)
+		cluster, s, boundary, state = StepStringWithOptions(s, state, opts)
+		clusters = append(clusters, cluster)
+		boundaries = append(boundaries, boundary)
+	}
+	state = -1
+	var index int
+	for b := []byte(str); len(b) > 0; index++ {
+		var (
+			cluster  []byte
+			boundary int
+		)
+		cluster, b, boundary, state = StepWithOptions(b, state, opts)
+		if index >= len(clusters) || string(cluster) != clusters[index] || boundary != boundaries[index] {
+			t.Fatalf("%q: StepWithOptions and StepStringWithOptions disagree at cluster %d", str, index)
+		}
+	}
+	if index != len(clusters) {
+		t.Fatalf("%q: StepWithOptions returned %d clusters, StepStringWithOptions %d", str, index, len(clusters))
+	}
+	return
+}
+
+// Test that the zero Options value reproduces Step.
+func TestStepWithOptionsZero(t *testing.T) {
+	for testNum, testCase := range testCases {
+		clusters, boundaries := stepWithOptions(t, testCase.original, Options{})
+		var index int
+		str, state := testCase.original, -1
+		for ; len(str) > 0; index++ {
+			var (
+				cluster  string
+				boundary int
+			)
+			cluster, str, boundary, state = StepString(str, state)
+			if cluster != clusters[index] || boundary != boundaries[index] {
+				t.Errorf("Test case %d %q: cluster %d is %q (boundaries %x), expected %q (boundaries %x)", testNum, testCase.original, index, clusters[index], boundaries[index], cluster, boundary)
+				break
+			}
+		}
+	}
+}
+
+// Test the width options.
+func TestStepWithOptionsWidth(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		opts     Options
+		widths   []int
+	}{
+		{"a\tb", Options{}, []int{1, 0, 1}},
+		{"a\tb", Options{TabWidth: 4}, []int{1, 4, 1}},
+		{"\t\t", Options{TabWidth: 8}, []int{8, 8}},
+		{"\u00b1\u00d7a", Options{}, []int{1, 1, 1}}, // ± and × are ambiguous.
+		{"\u00b1\u00d7a", Options{AmbiguousWidth: 2}, []int{2, 2, 1}},
+		{"\u00b1\u00d7a", Options{AmbiguousWidth: 1}, []int{1, 1, 1}},
+		{"a\u0300\u00b1\u0300", Options{AmbiguousWidth: 2}, []int{1, 2}}, // Combining marks have no width.
+		{"\u4e16\ufffd\xff", Options{AmbiguousWidth: 2}, []int{2, 2, 1}}, // Invalid bytes are always 1.
+		{"\u2194\ufe0f\u2194", Options{AmbiguousWidth: 2}, []int{2, 1}},  // Emoji are not affected.
+		{"\u00b1\t", Options{AmbiguousWidth: 2, TabWidth: 3}, []int{2, 3}},
+	} {
+		_, boundaries := stepWithOptions(t, testCase.original, testCase.opts)
+		var widths []int
+		for _, boundary := range boundaries {
+			widths = append(widths, boundary>>ShiftWidth)
+		}
+		if len(widths) != len(testCase.widths) {
+			t.Errorf("%q %+v: got widths %v, expected %v", testCase.original, testCase.opts, widths, testCase.widths)
+			continue
+		}
+		for i := range widths {
+			if widths[i] != testCase.widths[i] {
+				t.Errorf("%q %+v: got widths %v, expected %v", testCase.original, testCase.opts, widths, testCase.widths)
+				break
+			}
+		}
+	}
+}
+
+// Test that the width options do not change the package variable.
+func TestStepWithOptionsAmbiguousGlobal(t *testing.T) {
+	defer func(w int) { EastAsianAmbiguousWidth = w }(EastAsianAmbiguousWidth)
+	EastAsianAmbiguousWidth = 2
+	_, boundaries := stepWithOptions(t, "\u00b1", Options{AmbiguousWidth: 1})
+	if w := boundaries[0] >> ShiftWidth; w != 1 {
+		t.Errorf("Expected width 1, got %d", w)
+	}
+	if w := StringWidth("\u00b1"); w != 2 {
+		t.Errorf("Expected StringWidth 2, got %d", w)
+	}
+}
+
+// Test the ParagraphBreaks option.
+func TestStepWithOptionsParagraphBreaks(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string // Without ParagraphBreaks.
+		tailored []string // With ParagraphBreaks.
+	}{
+		{
+			"This sentence\nspans two lines.\nThis one doesn't.",
+			[]string{"This sentence\n", "spans two lines.\n", "This one doesn't."},
+			[]string{"This sentence\nspans two lines.\n", "This one doesn't."},
+		},
+		{
+			"one\r\ntwo\u0085three\u2028four",
+			[]string{"one\r\n", "two\u0085", "three\u2028", "four"},
+			[]string{"one\r\ntwo\u0085three\u2028four"},
+		},
+		{
+			"Ends here.\nNext",
+			[]string{"Ends here.\n", "Next"},
+			[]string{"Ends here.\n", "Next"},
+		},
+		{
+			"Ends here. \nNext",
+			[]string{"Ends here. \n", "Next"},
+			[]string{"Ends here. \n", "Next"},
+		},
+		{
+			"paragraph\n\nnext paragraph",
+			[]string{"paragraph\n", "\n", "next paragraph"},
+			[]string{"paragraph\n\n", "next paragraph"},
+		},
+		{
+			"paragraph\u2029next paragraph",
+			[]string{"paragraph\u2029", "next paragraph"},
+			[]string{"paragraph\u2029", "next paragraph"},
+		},
+		{
+			"\nfirst\nsecond\n",
+			[]string{"\n", "first\n", "second\n"},
+			[]string{"\n", "first\nsecond\n"},
+		},
+	} {
+		for _, opts := range []Options{{}, {ParagraphBreaks: true}} {
+			clusters, boundaries := stepWithOptions(t, testCase.original, opts)
+			var (
+				sentences []string
+				current   string
+			)
+			for i, cluster := range clusters {
+				current += cluster
+				if boundaries[i]&MaskSentence != 0 {
+					sentences = append(sentences, current)
+					current = ""
+				}
+			}
+			expected := testCase.expected
+			if opts.ParagraphBreaks {
+				expected = testCase.tailored
+			}
+			if strings.Join(sentences, "|") != strings.Join(expected, "|") {
+				t.Errorf("%q %+v: got sentences %q, expected %q", testCase.original, opts, sentences, expected)
+			}
+		}
+	}
+}
+
+// Test the NumberedLists option.
+func TestStepWithOptionsNumberedLists(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string // Without NumberedLists.
+		tailored []string // With NumberedLists.
+	}{
+		{
+			"1. First item. 2. Second item.",
+			[]string{"1. ", "First item. ", "2. ", "Second item."},
+			[]string{"1. First item. ", "2. Second item."},
+		},
+		{
+			"1. First item 2. Second item",
+			[]string{"1. ", "First item 2. ", "Second item"},
+			[]string{"1. First item 2. ", "Second item"},
+		},
+		{
+			"Steps:\n1. Open it.\n12. Close it.",
+			[]string{"Steps:\n", "1. ", "Open it.\n", "12. ", "Close it."},
+			[]string{"Steps:\n", "1. Open it.\n", "12. Close it."},
+		},
+		{
+			"See item 2. Then go.",
+			[]string{"See item 2. ", "Then go."},
+			[]string{"See item 2. ", "Then go."},
+		},
+		{
+			"Count: 1. 2. 3.",
+			[]string{"Count: 1. ", "2. ", "3."},
+			[]string{"Count: 1. ", "2. 3."},
+		},
+		{
+			"Item 1.",
+			[]string{"Item 1."},
+			[]string{"Item 1."},
+		},
+		{
+			"1. ",
+			[]string{"1. "},
+			[]string{"1. "},
+		},
+		{
+			"1.\nFirst",
+			[]string{"1.\n", "First"},
+			[]string{"1.\n", "First"},
+		},
+	} {
+		for _, opts := range []Options{{}, {NumberedLists: true}} {
+			clusters, boundaries := stepWithOptions(t, testCase.original, opts)
+			var (
+				sentences []string
+				current   string
+			)
+			for i, cluster := range clusters {
+				current += cluster
+				if boundaries[i]&MaskSentence != 0 {
+					sentences = append(sentences, current)
+					current = ""
+				}
+			}
+			expected := testCase.expected
+			if opts.NumberedLists {
+				expected = testCase.tailored
+			}
+			if strings.Join(sentences, "|") != strings.Join(expected, "|") {
+				t.Errorf("%q %+v: got sentences %q, expected %q", testCase.original, opts, sentences, expected)
+			}
+		}
+	}
+}
+
+// Test Options.StringWidth.
+func TestOptionsStringWidth(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		opts     Options
+		expected int
+	}{
+		{"", Options{AmbiguousWidth: 2}, 0},
+		{"a\u03b1\u4e16", Options{}, StringWidth("a\u03b1\u4e16")},
+		{"a\u03b1\u4e16", Options{AmbiguousWidth: 2}, 5},
+		{"a\tb\t", Options{TabWidth: 4}, 10},
+		{"a\tb\t", Options{TabStop: 4}, 8},
+		{"abcd\t", Options{TabStop: 4}, 8}, // Tab at a tab stop.
+		{"\u4e16\tx", Options{TabStop: 4}, 5},
+		{"ab\n\tx", Options{TabStop: 4}, 7}, // The column restarts after a line break.
+		{"a\tb", Options{TabWidth: 1, TabStop: 8}, 9},
+	} {
+		if width := testCase.opts.StringWidth(testCase.original); width != testCase.expected {
+			t.Errorf("%q with %+v: got width %d, expected %d", testCase.original, testCase.opts, width, testCase.expected)
+		}
+	}
+}
+
+// Test Options.MaxLineWidth, in particular the expansion of tabs relative to
+// the start of each line.
+func TestOptionsMaxLineWidth(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		opts     Options
+		expected int
+	}{
+		{"", Options{TabStop: 8}, 0},
+		{"a\n\u03b1\u03b1", Options{}, MaxLineWidth("a\n\u03b1\u03b1")},
+		{"a\n\u03b1\u03b1", Options{AmbiguousWidth: 2}, 4},
+		{"\tif x {\n\t\treturn\n\t}", Options{TabStop: 4}, 14},
+		{"\tif x {\n\t\treturn\n\t}", Options{TabStop: 8}, 22},
+		{"\tif x {\n\t\treturn\n\t}", Options{TabWidth: 1}, 8},
+		{"abcdefghi\n\tx", Options{TabStop: 8}, 9},
+		{"abcdefg\tx\nabcdefghi", Options{TabStop: 8}, 9},
+		{"abcdefgh\tx\nabcdefghi", Options{TabStop: 8}, 17},
+		{"\u4e16\u754c\tx\n\tx", Options{TabStop: 4}, 9},
+	} {
+		if width := testCase.opts.MaxLineWidth(testCase.original); width != testCase.expected {
+			t.Errorf("%q with %+v: got width %d, expected %d", testCase.original, testCase.opts, width, testCase.expected)
+		}
+	}
+}
+
+func TestOptionsFits(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		opts     Options
+		width    int
+		expected bool
+	}{
+		{"a\n\u03b1\u03b1", Options{}, 2, true},
+		{"a\n\u03b1\u03b1", Options{AmbiguousWidth: 2}, 3, false},
+		{"a\n\u03b1\u03b1", Options{AmbiguousWidth: 2}, 4, true},
+		{"\tif x {\n\t\treturn\n\t}", Options{TabStop: 4}, 14, true},
+		{"\tif x {\n\t\treturn\n\t}", Options{TabStop: 4}, 13, false},
+		{"abcdefgh\tx\nabcdefghi", Options{TabStop: 8}, 16, false},
+		{"abcdefghi\n\tx", Options{TabStop: 8}, 9, true},
+	} {
+		if fits := testCase.opts.Fits(testCase.original, testCase.width); fits != testCase.expected {
+			t.Errorf("%q with %+v: Fits(%d) is %t, expected %t", testCase.original, testCase.opts, testCase.width, fits, testCase.expected)
+		}
+	}
+}
@@ -116,6 +116,9 @@ const (
 	prInCBLinker    // Virama - links consonants in conjuncts
 	prInCBConsonant // Consonant - can form conjuncts
 	prInCBExtend    // Extend - extends within conjuncts
+
+	// Default_Ignorable_Code_Point property
+	prDefaultIgnorable
 )
 
 // Unicode General Categories needed for segmentation decisions.
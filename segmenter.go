@@ -0,0 +1,132 @@
+package runeseg
+
+// BoundaryKind determines the type of segments returned by a [Segmenter].
+type BoundaryKind int
+
+// The types of segments a [Segmenter] can return.
+const (
+	// BoundaryGrapheme splits text into grapheme clusters, see
+	// [FirstGraphemeClusterInString].
+	BoundaryGrapheme BoundaryKind = iota
+
+	// BoundaryWord splits text into words, see [FirstWordInString].
+	BoundaryWord
+
+	// BoundarySentence splits text into sentences, see
+	// [FirstSentenceInString].
+	BoundarySentence
+
+	// BoundaryLine splits text into line segments, i.e. at line break
+	// opportunities, see [FirstLineSegmentInString].
+	BoundaryLine
+)
+
+// Segmenter implements an iterator over the segments of a string, where the
+// type of segment (grapheme clusters, words, sentences, or line segments) is
+// determined by its [BoundaryKind].
+//
+// After constructing the class via [NewSegmenter], [Segmenter.Next] is called
+// in a loop until it returns false. Inside the loop, the current segment is
+// available via [Segmenter.Text] or [Segmenter.Bytes] and its position via
+// [Segmenter.Start] and [Segmenter.End].
+//
+// This class wraps the [StepString] parser. If you are only interested in one
+// type of segment, the specialized functions starting with "First" are usually
+// faster.
+type Segmenter struct {
+	// The original string.
+	original string
+
+	// The type of segments to return.
+	kind BoundaryKind
+
+	// The byte offsets of the current segment in the original string.
+	start, end int
+
+	// The current boundary information of the [Step] parser.
+	boundaries int
+
+	// The current state of the [Step] parser.
+	state int
+}
+
+// NewSegmenter returns a new iterator over the segments of the given kind in
+// the string "str".
+func NewSegmenter(str string, kind BoundaryKind) *Segmenter {
+	return &Segmenter{
+		original: str,
+		kind:     kind,
+		state:    -1,
+	}
+}
+
+// Next advances the iterator by one segment and returns false if no segments
+// are left. This function must be called before the first segment is
+// accessed.
+func (s *Segmenter) Next() bool {
+	s.start = s.end
+	if s.end >= len(s.original) {
+		return false
+	}
+	rest := s.original[s.end:]
+	for len(rest) > 0 {
+		var cluster string
+		cluster, rest, s.boundaries, s.state = StepString(rest, s.state)
+		s.end += len(cluster)
+		if s.isBoundary() {
+			break
+		}
+	}
+	return true
+}
+
+// isBoundary returns whether the current boundary information of the [Step]
+// parser indicates the end of a segment of the iterator's kind.
+func (s *Segmenter) isBoundary() bool {
+	switch s.kind {
+	case BoundaryWord:
+		return s.boundaries&MaskWord != 0
+	case BoundarySentence:
+		return s.boundaries&MaskSentence != 0
+	case BoundaryLine:
+		return s.boundaries&MaskLine != LineDontBreak
+	}
+	return true
+}
+
+// Text returns the current segment as a substring of the original string. If
+// the iterator is already past the end or [Segmenter.Next] has not yet been
+// called, an empty string is returned.
+func (s *Segmenter) Text() string {
+	return s.original[s.start:s.end]
+}
+
+// Bytes returns a byte slice which corresponds to the current segment. If the
+// iterator is already past the end or [Segmenter.Next] has not yet been
+// called, nil is returned.
+func (s *Segmenter) Bytes() []byte {
+	if s.start == s.end {
+		return nil
+	}
+	return []byte(s.original[s.start:s.end])
+}
+
+// Start returns the byte offset of the first byte of the current segment in
+// the original string.
+func (s *Segmenter) Start() int {
+	return s.start
+}
+
+// End returns the byte offset of the first byte after the current segment in
+// the original string, i.e. str[Start():End()] is the current segment of the
+// original string "str".
+func (s *Segmenter) End() int {
+	return s.end
+}
+
+// MustBreak returns true if the current segment ends with a mandatory line
+// break or at the end of the text. This is only meaningful for
+// [BoundaryLine].
+func (s *Segmenter) MustBreak() bool {
+	return s.boundaries&MaskLine == LineMustBreak
+}
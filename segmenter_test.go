@@ -0,0 +1,64 @@
+package runeseg
+
+import "testing"
+
+// segmenterTestStrings are the strings used to compare the Segmenter with the
+// specialized "First" functions.
+var segmenterTestStrings = []string{
+	"",
+	"Hello, world!",
+	"This is sentence 1.0. And this is sentence two.",
+	"First line.\nSecond line.\r\n",
+	"\U0001f1e9\U0001f1ea\U0001f3f3\ufe0f\u200d\U0001f308 ok",
+	"Wir’re nöt 世界 (here)...",
+}
+
+// Test that the Segmenter returns the same segments as the "First" functions.
+func TestSegmenter(t *testing.T) {
+	for _, str := range segmenterTestStrings {
+		for _, kind := range []BoundaryKind{BoundaryGrapheme, BoundaryWord, BoundarySentence, BoundaryLine} {
+			var expected []string
+			rest, state := str, -1
+			for len(rest) > 0 {
+				var segment string
+				switch kind {
+				case BoundaryGrapheme:
+					segment, rest, _, state = FirstGraphemeClusterInString(rest, state)
+				case BoundaryWord:
+					segment, rest, state = FirstWordInString(rest, state)
+				case BoundarySentence:
+					segment, rest, state = FirstSentenceInString(rest, state)
+				case BoundaryLine:
+					segment, rest, _, state = FirstLineSegmentInString(rest, state)
+				}
+				expected = append(expected, segment)
+			}
+
+			s := NewSegmenter(str, kind)
+			var index, offset int
+			for s.Next() {
+				if index >= len(expected) {
+					t.Errorf("%q (kind %d): unexpected segment %q", str, kind, s.Text())
+					break
+				}
+				if s.Text() != expected[index] {
+					t.Errorf("%q (kind %d): segment %d is %q, expected %q", str, kind, index, s.Text(), expected[index])
+				}
+				if string(s.Bytes()) != s.Text() {
+					t.Errorf("%q (kind %d): Bytes() and Text() differ: %q vs. %q", str, kind, s.Bytes(), s.Text())
+				}
+				if s.Start() != offset || s.End() != offset+len(s.Text()) {
+					t.Errorf("%q (kind %d): segment %d has positions %d-%d, expected %d-%d", str, kind, index, s.Start(), s.End(), offset, offset+len(s.Text()))
+				}
+				offset = s.End()
+				index++
+			}
+			if index != len(expected) {
+				t.Errorf("%q (kind %d): got %d segments, expected %d", str, kind, index, len(expected))
+			}
+			if s.Next() || s.Text() != "" || s.Bytes() != nil {
+				t.Errorf("%q (kind %d): iterator not exhausted", str, kind)
+			}
+		}
+	}
+}
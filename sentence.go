@@ -88,3 +88,51 @@ func FirstSentenceInString(str string, state int) (sentence, rest string, newSta
 		}
 	}
 }
+
+// AppendSentenceBoundaries is like [AppendWordBoundaries] but appends the
+// byte offsets at which the sentences returned by [FirstSentenceInString] end.
+func AppendSentenceBoundaries(dst []int, s string) []int {
+	var (
+		sentence string
+		offset   int
+	)
+	state := -1
+	for len(s) > 0 {
+		sentence, s, state = FirstSentenceInString(s, state)
+		offset += len(sentence)
+		dst = append(dst, offset)
+	}
+	return dst
+}
+
+// SentenceBoundary determines whether there is a sentence boundary before the
+// rune "r", given the state returned by the previous call. It lets you detect
+// sentences in a stream of runes without encoding them in UTF-8 first.
+//
+// The "lookahead" function as well as the state are handled as described in
+// [WordBoundary]. The state used by this function is not compatible with the
+// states used by [Step] or [FirstSentence] and vice versa.
+func SentenceBoundary(state int, r rune, lookahead func() (rune, bool)) (boundary bool, newState int) {
+	if state < 0 {
+		state = -1
+	}
+
+	// SB8 looks for the first rune which is OLetter, Upper, Lower, ParaSep, or
+	// SATerm, starting with "r".
+	var next []byte
+	if state == sbATerm || state == sbSB8Close || state == sbSB8Sp || state == sbSB7 {
+		stop := func(r rune) bool {
+			switch property(sentenceBreakCodePoints, r) {
+			case prOLetter, prUpper, prLower, prSep, prCR, prLF, prATerm, prSTerm:
+				return true
+			}
+			return false
+		}
+		if !stop(r) {
+			next = lookaheadRunes(lookahead, stop)
+		}
+	}
+
+	newState, boundary = transitionSentenceBreakState(state, r, nil, string(next))
+	return boundary || state < 0, newState
+}
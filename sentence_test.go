@@ -1,6 +1,7 @@
 package runeseg
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -146,6 +147,184 @@ func TestSentenceCasesString(t *testing.T) {
 	}
 }
 
+// Test that an ellipsis, be it U+2026 or three full stops, does not end a
+// sentence unless followed by the usual boundary pattern (SB11).
+func TestSentenceEllipsis(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"Wait\u2026 what?", []string{"Wait\u2026 what?"}},
+		{"Wait\u2026 What?", []string{"Wait\u2026 What?"}}, // U+2026 is not STerm or ATerm.
+		{"Wait\u2026what?", []string{"Wait\u2026what?"}},
+		{"Wait\u2026? Yes.", []string{"Wait\u2026? ", "Yes."}},
+		{"Wait... what?", []string{"Wait... what?"}}, // SB8: ATerm before lowercase.
+		{"Wait...what?", []string{"Wait...what?"}},
+		{"Wait... What?", []string{"Wait... ", "What?"}}, // SB11.
+		{"He said... and left. Then", []string{"He said... and left. ", "Then"}},
+	} {
+		var sentences []string
+		str, state := testCase.original, -1
+		for len(str) > 0 {
+			var sentence string
+			sentence, str, state = FirstSentenceInString(str, state)
+			sentences = append(sentences, sentence)
+		}
+		var bytesSentences []string
+		b, state := []byte(testCase.original), -1
+		for len(b) > 0 {
+			var sentence []byte
+			sentence, b, state = FirstSentence(b, state)
+			bytesSentences = append(bytesSentences, string(sentence))
+		}
+		if len(sentences) != len(testCase.expected) || len(bytesSentences) != len(testCase.expected) {
+			t.Errorf("%q: got sentences %q (bytes: %q), expected %q", testCase.original, sentences, bytesSentences, testCase.expected)
+			continue
+		}
+		for i := range sentences {
+			if sentences[i] != testCase.expected[i] || bytesSentences[i] != testCase.expected[i] {
+				t.Errorf("%q: sentence %d is %q (bytes: %q), expected %q", testCase.original, i, sentences[i], bytesSentences[i], testCase.expected[i])
+			}
+		}
+	}
+}
+
+// Test abbreviations such as "etc." whose full stop may or may not end a
+// sentence. UAX #29 does not know about abbreviations: The decision is made by
+// the rules alone, not by the meaning of the text. A full stop (ATerm)
+// followed by spaces and a lowercase letter does not end a sentence (SB8) but
+// one followed by spaces and an uppercase letter does (SB11).
+func TestSentenceAbbreviation(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"I bought apples, oranges, etc. Then I left.", []string{"I bought apples, oranges, etc. ", "Then I left."}},
+		{"I bought apples, oranges, etc. and more.", []string{"I bought apples, oranges, etc. and more."}},
+		{"Apples, etc.  then more.", []string{"Apples, etc.  then more."}},     // SB8 skips several spaces.
+		{"Apples (etc.) Then more.", []string{"Apples (etc.) ", "Then more."}}, // SB9: Closing brackets.
+		{"Apples etc.\nthen more.", []string{"Apples etc.\n", "then more."}},   // SB4: Line breaks always end sentences.
+		{"See e.g. the list.", []string{"See e.g. the list."}},
+		{"Ask Dr. Smith.", []string{"Ask Dr. ", "Smith."}},               // Uppercase names are indistinguishable.
+		{"Apples, etc., and more.", []string{"Apples, etc., and more."}}, // SB8a: SContinue.
+	} {
+		var sentences []string
+		str, state := testCase.original, -1
+		for len(str) > 0 {
+			var sentence string
+			sentence, str, state = FirstSentenceInString(str, state)
+			sentences = append(sentences, sentence)
+		}
+		if strings.Join(sentences, "|") != strings.Join(testCase.expected, "|") {
+			t.Errorf("%q: got sentences %q, expected %q", testCase.original, sentences, testCase.expected)
+		}
+	}
+}
+
+// Test that closing quotation marks and brackets after a terminator belong to
+// the sentence they close (SB9, SB11).
+func TestSentenceClosingQuotes(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{`He said "Stop." Then left.`, []string{`He said "Stop." `, "Then left."}},
+		{"He said \u201cStop.\u201d Then left.", []string{"He said \u201cStop.\u201d ", "Then left."}},
+		{"He said 'Stop!' Then left.", []string{"He said 'Stop!' ", "Then left."}},
+		{"He said \u2018Stop?\u2019 Then left.", []string{"He said \u2018Stop?\u2019 ", "Then left."}},
+		{"He said \u00abStop.\u00bb Then left.", []string{"He said \u00abStop.\u00bb ", "Then left."}},
+		{`"Stop.") Then`, []string{`"Stop.") `, "Then"}},       // Several closing characters.
+		{`"Stop."Then`, []string{`"Stop."`, "Then"}},           // SB11 without a space.
+		{`"Stop." then left.`, []string{`"Stop." then left.`}}, // SB8: lowercase continues the sentence.
+	} {
+		var sentences []string
+		str, state := testCase.original, -1
+		for len(str) > 0 {
+			var sentence string
+			sentence, str, state = FirstSentenceInString(str, state)
+			sentences = append(sentences, sentence)
+		}
+		var stepSentences []string
+		str, state = testCase.original, -1
+		var current string
+		for len(str) > 0 {
+			var (
+				cluster    string
+				boundaries int
+			)
+			cluster, str, boundaries, state = StepString(str, state)
+			current += cluster
+			if boundaries&MaskSentence != 0 {
+				stepSentences = append(stepSentences, current)
+				current = ""
+			}
+		}
+		for _, result := range [][]string{sentences, stepSentences} {
+			if len(result) != len(testCase.expected) {
+				t.Errorf("%q: got sentences %q, expected %q", testCase.original, result, testCase.expected)
+				continue
+			}
+			for i := range result {
+				if result[i] != testCase.expected[i] {
+					t.Errorf("%q: sentence %d is %q, expected %q", testCase.original, i, result[i], testCase.expected[i])
+				}
+			}
+		}
+	}
+}
+
+// Test that SentenceBoundary reproduces the output of FirstSentence for the
+// official Unicode test cases.
+func TestSentenceBoundary(t *testing.T) {
+	for testNum, testCase := range sentenceBreakTestCases {
+		var expected []string
+		b, state := []byte(testCase.original), -1
+		for len(b) > 0 {
+			var sentence []byte
+			sentence, b, state = FirstSentence(b, state)
+			expected = append(expected, string(sentence))
+		}
+
+		var (
+			sentences []string
+			boundary  bool
+		)
+		runes := []rune(testCase.original)
+		state = -1
+		for i, r := range runes {
+			boundary, state = SentenceBoundary(state, r, runeLookahead(runes[i+1:]))
+			if boundary {
+				sentences = append(sentences, "")
+			}
+			sentences[len(sentences)-1] += string(r)
+		}
+
+		if len(sentences) != len(expected) {
+			t.Errorf("Test case %d %q: got sentences %q, FirstSentence returned %q", testNum, testCase.original, sentences, expected)
+			continue
+		}
+		for i := range sentences {
+			if sentences[i] != expected[i] {
+				t.Errorf("Test case %d %q: sentence %d is %q, FirstSentence returned %q", testNum, testCase.original, i, sentences[i], expected[i])
+			}
+		}
+	}
+}
+
+// Test the AppendSentenceBoundaries function.
+func TestAppendSentenceBoundaries(t *testing.T) {
+	for testNum, testCase := range sentenceBreakTestCases {
+		expected := segmentEnds(testCase.expected)
+		if offsets := AppendSentenceBoundaries(nil, testCase.original); !equalOffsets(offsets, expected) {
+			t.Errorf("Test case %d %q: got offsets %v, expected %v", testNum, testCase.original, offsets, expected)
+		}
+	}
+	offsets := AppendSentenceBoundaries([]int{-1}, "One. Two? Three")
+	if !equalOffsets(offsets, []int{-1, 5, 10, 15}) {
+		t.Errorf("Got offsets %v", offsets)
+	}
+}
+
 // Benchmark the use of the sentence break function for byte slices.
 func BenchmarkSentenceFunctionBytes(b *testing.B) {
 	for i := 0; i < b.N; i++ {
@@ -2941,3 +2941,11 @@ var sentenceBreakCodePoints = [][3]int{
 	{0xE0020, 0xE007F, prExtend},  // Cf  [96] TAG SPACE..CANCEL TAG
 	{0xE0100, 0xE01EF, prExtend},  // Mn [240] VARIATION SELECTOR-17..VARIATION SELECTOR-256
 }
+
+// sentenceBreakCodePointsVersion is the Unicode version of the data file
+// sentenceBreakCodePoints was generated from.
+// sentenceBreakCodePointsDate is the date of the generation.
+const (
+	sentenceBreakCodePointsVersion = "17.0.0"
+	sentenceBreakCodePointsDate    = "2026-01-05"
+)
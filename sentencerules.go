@@ -252,8 +252,9 @@ func transitionSentenceBreakState(state int, r rune, b []byte, str string) (newS
 
 	// SB8.
 	if rule > 80 && (state == sbATerm || state == sbSB8Close || state == sbSB8Sp || state == sbSB7) {
-		// Check the right side of the rule.
-		var length int
+		// Check the right side of the rule, looking at no more than
+		// MaxLookahead runes.
+		var length, lookahead int
 		for nextProperty != prOLetter &&
 			nextProperty != prUpper &&
 			nextProperty != prLower &&
@@ -263,6 +264,10 @@ func transitionSentenceBreakState(state int, r rune, b []byte, str string) (newS
 			nextProperty != prATerm &&
 			nextProperty != prSTerm {
 			// Move on to the next rune.
+			if lookahead >= MaxLookahead {
+				break
+			}
+			lookahead++
 			if b != nil { // Byte slice version.
 				r, length = utf8.DecodeRune(b)
 				b = b[length:]
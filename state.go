@@ -0,0 +1,38 @@
+package runeseg
+
+import "encoding/binary"
+
+// stateFormat identifies the layout of the states returned by the functions of
+// this package. It must be incremented whenever the layout or the meaning of
+// the state values changes, see [EncodeState].
+const stateFormat = 1
+
+// EncodeState serializes a state returned by [Step], [StepString],
+// [LineBreakOptions.Step], or any of the First* functions such as
+// [FirstGraphemeCluster], e.g. to resume parsing in another process. Use
+// [DecodeState] to restore it.
+//
+// The internal layout of states is not part of the API and may change between
+// versions of this package. The encoded state therefore includes a format
+// identifier which changes when the layout changes. [DecodeState] rejects
+// states of other formats, such that a state is never misinterpreted.
+func EncodeState(state int) []byte {
+	b := make([]byte, 1+binary.MaxVarintLen64)
+	b[0] = stateFormat
+	return b[:1+binary.PutVarint(b[1:], int64(state))]
+}
+
+// DecodeState restores a state serialized with [EncodeState]. If the data is
+// invalid or was encoded by a version of this package with a different state
+// format, -1 is returned. Parsing then restarts as if at the beginning of a
+// text, which may lead to different boundaries at the resumption point.
+func DecodeState(b []byte) int {
+	if len(b) < 2 || b[0] != stateFormat {
+		return -1
+	}
+	state, n := binary.Varint(b[1:])
+	if n != len(b)-1 || int64(int(state)) != state {
+		return -1
+	}
+	return int(state)
+}
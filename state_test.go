@@ -0,0 +1,75 @@
+package runeseg
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+)
+
+// Test that a Step iteration resumed with a serialized state yields the same
+// results as an uninterrupted one.
+func TestEncodeState(t *testing.T) {
+	for _, testCase := range []string{
+		"Hello, world! This is a test.",
+		"\U0001F1E9\U0001F1EA\U0001F1E9\U0001F1EA\U0001F1E9",
+		"\U0001F469\u200d\U0001F467 (100)% \u05d0-\u05d1",
+		"etc. and so on",
+	} {
+		// The uninterrupted iteration.
+		var (
+			clusters   []string
+			boundaries []int
+		)
+		str, state := testCase, -1
+		for len(str) > 0 {
+			var (
+				c string
+				b int
+			)
+			c, str, b, state = StepString(str, state)
+			clusters = append(clusters, c)
+			boundaries = append(boundaries, b)
+		}
+
+		// Interrupt after every cluster.
+		for stop := 1; stop < len(clusters); stop++ {
+			str, state = testCase, -1
+			for i := 0; i < stop; i++ {
+				_, str, _, state = StepString(str, state)
+			}
+			state = DecodeState(EncodeState(state))
+			for i := stop; len(str) > 0; i++ {
+				var (
+					c string
+					b int
+				)
+				c, str, b, state = StepString(str, state)
+				if c != clusters[i] || b != boundaries[i] {
+					t.Errorf("%q resumed after cluster %d: cluster %d is %q (%x), expected %q (%x)", testCase, stop, i, c, b, clusters[i], boundaries[i])
+					break
+				}
+			}
+		}
+	}
+
+	// States which can't be decoded.
+	for _, b := range [][]byte{
+		nil,
+		{stateFormat},
+		{stateFormat + 1, 2},
+		append(EncodeState(42), 0),
+		{stateFormat, 0x80},
+	} {
+		if state := DecodeState(b); state != -1 {
+			t.Errorf("%x decoded to %d, expected -1", b, state)
+		}
+	}
+	for _, state := range []int{-1, 0, 1 << (strconv.IntSize - 24), int(^uint(0) >> 1)} {
+		if decoded := DecodeState(EncodeState(state)); decoded != state {
+			t.Errorf("%d decoded to %d", state, decoded)
+		}
+	}
+	if !bytes.Equal(EncodeState(1), []byte{stateFormat, 2}) {
+		t.Errorf("Unexpected encoding %x", EncodeState(1))
+	}
+}
@@ -46,6 +46,28 @@ const (
 	maskLineState     = 0xffff // 16 bits: 8 for state + 8 for context flags
 )
 
+// MaxLookahead is the maximum number of runes following a rune that the
+// boundary algorithms of this package examine to determine the boundary
+// before that rune. A stream parser therefore needs to buffer at most this
+// many runes (or four times as many bytes) after the current position to make
+// the same decisions as for the complete text. The rules which look ahead are:
+//
+//   - Grapheme clusters: None.
+//   - Word boundaries: WB6, WB7b, and WB12, which look at the first rune
+//     after a MidLetter, MidNum, or quotation mark which is not Extend,
+//     Format, or ZWJ.
+//   - Sentence boundaries: SB8, which looks for a lowercase letter after a
+//     full stop, skipping any characters which are not letters, paragraph
+//     separators, or terminators.
+//   - Line breaks: LB15.2, which needs to know if the text ends after a
+//     closing quotation mark.
+//
+// The lookahead of the word and sentence rules is unbounded in theory. It is
+// capped at MaxLookahead runes. If no decision can be made within this limit,
+// the rules behave as if the text ended there. The tailorings of
+// [LineBreakOptions] are not bound by this limit, see their documentation.
+const MaxLookahead = 32
+
 // Step returns the first grapheme cluster (user-perceived character) found in
 // the given byte slice. It also returns information about the boundary between
 // that grapheme cluster and the one following it as well as the monospace width
@@ -84,6 +106,20 @@ const (
 //
 // Given an empty byte slice "b", the function returns nil values.
 //
+// The end of "b" is treated as the end of the text. If the text arrives in
+// chunks, boundaries near the end of a chunk may change once the next chunk
+// is known. For grapheme clusters, only the last cluster of a chunk (the one
+// returned with an empty "rest" slice) is affected: It may be continued by the
+// next chunk, e.g. by a combining mark or by the second regional indicator of
+// a flag. Hold it back and prepend it to the next chunk. [ColumnTracker] does
+// this. Word and sentence boundaries and line breaks, however, look ahead up
+// to [MaxLookahead] runes. For example, the chunk "a." has a word boundary
+// after "a" but "a.b" doesn't (WB6). If you need these boundaries, also hold
+// back any cluster whose "rest" slice has MaxLookahead runes or fewer, along
+// with the rest of the chunk, and pass the state which was passed to the call
+// that returned the first cluster held back. The tailorings of
+// [LineBreakOptions] may look further ahead, see their documentation.
+//
 // While slightly less convenient than using the Graphemes class, this function
 // has much better performance and makes no allocations. It lends itself well to
 // large byte slices.
@@ -109,7 +145,7 @@ func Step(b []byte, state int) (cluster, rest []byte, boundaries int, newState i
 		} else {
 			prop = state >> shiftPropState
 		}
-		return b, nil, LineMustBreak | (1 << shiftWord) | (1 << shiftSentence) | (runeWidth(r, prop) << ShiftWidth), grAny | (wbAny << shiftWordState) | (sbAny << shiftSentenceState) | (lbAny << shiftLineState) | (prop << shiftPropState)
+		return b, nil, LineMustBreak | (1 << shiftWord) | (1 << shiftSentence) | (runeWidth(r, length, prop) << ShiftWidth), grAny | (wbAny << shiftWordState) | (sbAny << shiftSentenceState) | (lbAny << shiftLineState) | (prop << shiftPropState)
 	}
 
 	// If we don't know the state, determine it now.
@@ -129,7 +165,7 @@ func Step(b []byte, state int) (cluster, rest []byte, boundaries int, newState i
 	}
 
 	// Transition until we find a grapheme cluster boundary.
-	width := runeWidth(r, firstProp)
+	width := runeWidth(r, length, firstProp)
 	for {
 		var (
 			graphemeBoundary, wordBoundary, sentenceBoundary bool
@@ -163,7 +199,7 @@ func Step(b []byte, state int) (cluster, rest []byte, boundaries int, newState i
 				width = 2
 			}
 		} else if firstProp != prRegionalIndicator && firstProp != prL {
-			width += runeWidth(r, prop)
+			width += runeWidth(r, l, prop)
 		}
 
 		length += l
@@ -184,7 +220,7 @@ func StepString(str string, state int) (cluster, rest string, boundaries int, ne
 	r, length := utf8.DecodeRuneInString(str)
 	if len(str) <= length { // If we're already past the end, there is nothing else to parse.
 		prop := propertyGraphemes(r)
-		return str, "", LineMustBreak | (1 << shiftWord) | (1 << shiftSentence) | (runeWidth(r, prop) << ShiftWidth), grAny | (wbAny << shiftWordState) | (sbAny << shiftSentenceState) | (lbAny << shiftLineState)
+		return str, "", LineMustBreak | (1 << shiftWord) | (1 << shiftSentence) | (runeWidth(r, length, prop) << ShiftWidth), grAny | (wbAny << shiftWordState) | (sbAny << shiftSentenceState) | (lbAny << shiftLineState)
 	}
 
 	// If we don't know the state, determine it now.
@@ -204,7 +240,7 @@ func StepString(str string, state int) (cluster, rest string, boundaries int, ne
 	}
 
 	// Transition until we find a grapheme cluster boundary.
-	width := runeWidth(r, firstProp)
+	width := runeWidth(r, length, firstProp)
 	for {
 		var (
 			graphemeBoundary, wordBoundary, sentenceBoundary bool
@@ -238,7 +274,7 @@ func StepString(str string, state int) (cluster, rest string, boundaries int, ne
 				width = 2
 			}
 		} else if firstProp != prRegionalIndicator && firstProp != prL {
-			width += runeWidth(r, prop)
+			width += runeWidth(r, l, prop)
 		}
 
 		length += l
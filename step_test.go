@@ -1,7 +1,9 @@
 package runeseg
 
 import (
+	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 // Test official Grapheme Cluster Unicode test cases for grapheme clusters using
@@ -458,6 +460,375 @@ func TestStepStringSentence(t *testing.T) {
 	}
 }
 
+// Test the handling of invalid UTF-8: Each invalid byte is its own grapheme
+// cluster of width 1 which behaves like a letter for line breaking, and valid
+// runes following it are never swallowed.
+func TestStepInvalidUTF8(t *testing.T) {
+	defer func(width int) { EastAsianAmbiguousWidth = width }(EastAsianAmbiguousWidth)
+	for _, ambiguousWidth := range []int{1, 2} {
+		EastAsianAmbiguousWidth = ambiguousWidth
+		for _, testCase := range []struct {
+			original string
+			clusters []string
+			lines    string // "|" marks line break opportunities.
+		}{
+			{"\xff", []string{"\xff"}, "\xff"},
+			{"a\xffb", []string{"a", "\xff", "b"}, "a\xffb"},                   // Lone invalid byte.
+			{"\x80\x80", []string{"\x80", "\x80"}, "\x80\x80"},                 // Lone continuation bytes.
+			{"\xe2\x82a", []string{"\xe2", "\x82", "a"}, "\xe2\x82a"},          // Truncated 3-byte sequence.
+			{"\xf0\x9f\x91", []string{"\xf0", "\x9f", "\x91"}, "\xf0\x9f\x91"}, // Truncated 4-byte sequence.
+			{"\xf0\x9f\x91\U0001F44D", []string{"\xf0", "\x9f", "\x91", "\U0001F44D"}, "\xf0\x9f\x91|\U0001F44D"},
+			{"\xe2\u4e16", []string{"\xe2", "\u4e16"}, "\xe2|\u4e16"},
+			{"a \xff b", []string{"a", " ", "\xff", " ", "b"}, "a |\xff |b"},
+			{"\xff\u0301x", []string{"\xff\u0301", "x"}, "\xff\u0301x"},        // Combining marks extend it.
+			{"\xc0\xaf", []string{"\xc0", "\xaf"}, "\xc0\xaf"},                 // Overlong encoding.
+			{"\xed\xa0\x80", []string{"\xed", "\xa0", "\x80"}, "\xed\xa0\x80"}, // Encoded surrogate.
+		} {
+			var (
+				clusters   []string
+				lines      string
+				width      int
+				c          string
+				boundaries int
+			)
+			str, state := testCase.original, -1
+			for len(str) > 0 {
+				c, str, boundaries, state = StepString(str, state)
+				clusters = append(clusters, c)
+				lines += c
+				if len(str) > 0 && boundaries&MaskLine == LineCanBreak {
+					lines += "|"
+				}
+				width += boundaries >> ShiftWidth
+			}
+			if len(clusters) != len(testCase.clusters) {
+				t.Errorf("%q: got clusters %q, expected %q", testCase.original, clusters, testCase.clusters)
+				continue
+			}
+			expectedWidth := 0
+			for i := range clusters {
+				if clusters[i] != testCase.clusters[i] {
+					t.Errorf("%q: cluster %d is %q, expected %q", testCase.original, i, clusters[i], testCase.clusters[i])
+				}
+				expectedWidth += StringWidth(testCase.clusters[i])
+			}
+			if lines != testCase.lines {
+				t.Errorf("%q: got line breaks %q, expected %q", testCase.original, lines, testCase.lines)
+			}
+			if w := StringWidth(testCase.original); w != width || w != expectedWidth {
+				t.Errorf("%q: StringWidth is %d, Step width is %d, expected %d", testCase.original, w, width, expectedWidth)
+			}
+			if Valid(testCase.original) {
+				t.Errorf("%q: reported as valid", testCase.original)
+			}
+		}
+		if w := StringWidth("\xff"); w != 1 {
+			t.Errorf("Width of an invalid byte is %d with ambiguous width %d, expected 1", w, ambiguousWidth)
+		}
+		if w := StringWidth("\ufffd"); w != ambiguousWidth {
+			t.Errorf("Width of U+FFFD is %d with ambiguous width %d, expected %d", w, ambiguousWidth, ambiguousWidth)
+		}
+	}
+	if !Valid("a\u4e16\U0001F44D") || !Valid("") {
+		t.Error("Valid strings reported as invalid")
+	}
+}
+
+// checkClusterWidths checks that [StepString] splits the "original" string
+// into the expected grapheme clusters with the expected widths, and that
+// [StringWidth] returns the sum of these widths.
+func checkClusterWidths(t *testing.T, original string, expectedClusters []string, expectedWidths []int) {
+	t.Helper()
+	var (
+		clusters []string
+		widths   []int
+	)
+	str, state := original, -1
+	for len(str) > 0 {
+		var (
+			c          string
+			boundaries int
+		)
+		c, str, boundaries, state = StepString(str, state)
+		clusters = append(clusters, c)
+		widths = append(widths, boundaries>>ShiftWidth)
+	}
+	if len(clusters) != len(expectedClusters) {
+		t.Errorf("%q: got clusters %q, expected %q", original, clusters, expectedClusters)
+		return
+	}
+	total := 0
+	for i := range clusters {
+		if clusters[i] != expectedClusters[i] || widths[i] != expectedWidths[i] {
+			t.Errorf("%q: cluster %d is %q with width %d, expected %q with width %d", original, i, clusters[i], widths[i], expectedClusters[i], expectedWidths[i])
+		}
+		total += expectedWidths[i]
+	}
+	if w := StringWidth(original); w != total {
+		t.Errorf("%q: StringWidth is %d, expected %d", original, w, total)
+	}
+}
+
+// Test that a ZWJ which is not followed by an Extended_Pictographic character
+// ends the cluster (GB11 does not apply) and that the widths of both clusters
+// are calculated separately.
+func TestStepZWJNonPictographic(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		clusters []string
+		widths   []int
+	}{
+		{"\U0001F44D\u200dA", []string{"\U0001F44D\u200d", "A"}, []int{2, 1}},
+		{"\U0001F44D\u200d1", []string{"\U0001F44D\u200d", "1"}, []int{2, 1}},
+		{"\U0001F44D\u200d\u4e16", []string{"\U0001F44D\u200d", "\u4e16"}, []int{2, 2}},
+		{"\U0001F44D\u200d\u200dA", []string{"\U0001F44D\u200d\u200d", "A"}, []int{2, 1}},
+		{"A\u200d\U0001F44D", []string{"A\u200d", "\U0001F44D"}, []int{1, 2}},
+		{"\U0001F44D\u200d\U0001F44D", []string{"\U0001F44D\u200d\U0001F44D"}, []int{2}}, // GB11.
+	} {
+		checkClusterWidths(t, testCase.original, testCase.clusters, testCase.widths)
+	}
+}
+
+// Test that the zero width non-joiner U+200C, used in Persian to separate
+// morphemes within a word, extends the preceding grapheme cluster without
+// adding width and without introducing word or line boundaries.
+func TestStepZWNJ(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		clusters []string
+	}{
+		{"\u0645\u06cc\u200c\u062e\u0648\u0627\u0647\u0645", []string{"\u0645", "\u06cc\u200c", "\u062e", "\u0648", "\u0627", "\u0647", "\u0645"}}, // "I want" (Persian).
+		{"\u0647\u200c\u0647\u0627", []string{"\u0647\u200c", "\u0647", "\u0627"}},
+		{"\u0628\u0650\u200c\u0646", []string{"\u0628\u0650\u200c", "\u0646"}}, // Combining mark before ZWNJ.
+		{"\u0628\u200c\u0650\u0646", []string{"\u0628\u200c\u0650", "\u0646"}}, // Combining mark after ZWNJ.
+	} {
+		var clusters []string
+		str, state := testCase.original, -1
+		for len(str) > 0 {
+			var (
+				c          string
+				boundaries int
+			)
+			c, str, boundaries, state = StepString(str, state)
+			clusters = append(clusters, c)
+			if width := boundaries >> ShiftWidth; width != 1 {
+				t.Errorf("%q: cluster %q has width %d, expected 1", testCase.original, c, width)
+			}
+			if len(str) > 0 && boundaries&MaskWord != 0 {
+				t.Errorf("%q: unexpected word boundary after %q", testCase.original, c)
+			}
+			if len(str) > 0 && boundaries&MaskLine != LineDontBreak {
+				t.Errorf("%q: unexpected line break after %q", testCase.original, c)
+			}
+		}
+		if strings.Join(clusters, "|") != strings.Join(testCase.clusters, "|") {
+			t.Errorf("%q: got clusters %q, expected %q", testCase.original, clusters, testCase.clusters)
+		}
+		if n := GraphemeClusterCount(testCase.original); n != len(testCase.clusters) {
+			t.Errorf("%q: GraphemeClusterCount is %d, expected %d", testCase.original, n, len(testCase.clusters))
+		}
+	}
+}
+
+// Test that no boundary decision depends on runes further away than
+// MaxLookahead.
+func TestMaxLookahead(t *testing.T) {
+	long := strings.Repeat("\u0301", 2*MaxLookahead)
+	spaces := strings.Repeat(" ", 2*MaxLookahead)
+	texts := []string{
+		"a'" + long + "b",                                     // WB6/7.
+		"a" + long + "'" + long + "b",                         // WB6/7 after extenders.
+		"1," + long + "2",                                     // WB11/12.
+		"etc." + spaces + "and",                               // SB8.
+		"etc." + strings.Repeat(")", 2*MaxLookahead) + " and", // SB8 with closing punctuation.
+		"e.g." + strings.Repeat("1", 2*MaxLookahead) + "a",    // SB8 with numbers.
+		"\u201cQuote\u201d" + long,                            // LB15.2.
+	}
+	for _, testCase := range wordBreakTestCases {
+		texts = append(texts, testCase.original)
+	}
+	for _, testCase := range sentenceBreakTestCases {
+		texts = append(texts, testCase.original)
+	}
+	for _, text := range texts {
+		runes := []rune(text)
+		wordState, sentenceState, lineState := -1, -1, -1
+		for i, r := range runes {
+			rest := runes[i+1:]
+			truncated := rest
+			if len(truncated) > MaxLookahead {
+				truncated = truncated[:MaxLookahead]
+			}
+			full, limited := string(rest), string(truncated)
+
+			newState, wordBreak := transitionWordBreakState(wordState, r, nil, full)
+			limitedState, limitedBreak := transitionWordBreakState(wordState, r, nil, limited)
+			if newState != limitedState || wordBreak != limitedBreak {
+				t.Errorf("%q: word decision at rune %d depends on more than %d runes", text, i, MaxLookahead)
+			}
+			wordState = newState
+
+			newState, sentenceBreak := transitionSentenceBreakState(sentenceState, r, nil, full)
+			limitedState, limitedSentenceBreak := transitionSentenceBreakState(sentenceState, r, nil, limited)
+			if newState != limitedState || sentenceBreak != limitedSentenceBreak {
+				t.Errorf("%q: sentence decision at rune %d depends on more than %d runes", text, i, MaxLookahead)
+			}
+			sentenceState = newState
+
+			newState, lineBreak := transitionLineBreakStateContext(lineState, r, nil, full)
+			limitedState, limitedLineBreak := transitionLineBreakStateContext(lineState, r, nil, limited)
+			if newState != limitedState || lineBreak != limitedLineBreak {
+				t.Errorf("%q: line decision at rune %d depends on more than %d runes", text, i, MaxLookahead)
+			}
+			lineState = newState
+		}
+
+		// The rune-based functions must not request more runes.
+		wordState, sentenceState, lineState = -1, -1, -1
+		for i, r := range runes {
+			var calls int
+			lookahead := func() (rune, bool) {
+				calls++
+				if i+calls >= len(runes) {
+					return 0, false
+				}
+				return runes[i+calls], true
+			}
+			_, wordState = WordBoundary(wordState, r, lookahead)
+			if calls > MaxLookahead {
+				t.Errorf("%q: WordBoundary requested %d runes at rune %d", text, calls, i)
+			}
+			calls = 0
+			_, sentenceState = SentenceBoundary(sentenceState, r, lookahead)
+			if calls > MaxLookahead {
+				t.Errorf("%q: SentenceBoundary requested %d runes at rune %d", text, calls, i)
+			}
+			calls = 0
+			_, lineState = LineBreak(lineState, r, lookahead)
+			if calls > MaxLookahead {
+				t.Errorf("%q: LineBreak requested %d runes at rune %d", text, calls, i)
+			}
+		}
+	}
+}
+
+// Test that a flag split across chunks forms one grapheme cluster if the last
+// cluster of each chunk is held back as described in the [Step] documentation,
+// and that a lone regional indicator at the end of a chunk is returned with
+// an empty "rest" slice so that it can be recognized as incomplete.
+func TestStepChunkedRegionalIndicators(t *testing.T) {
+	cluster, rest, _, _ := Step([]byte("\U0001F1E9"), -1)
+	if string(cluster) != "\U0001F1E9" || len(rest) != 0 {
+		t.Errorf("Lone regional indicator: got cluster %q and rest %q", cluster, rest)
+	}
+
+	for _, original := range []string{
+		"\U0001F1E9\U0001F1EA",
+		"a\U0001F1E9\U0001F1EAb",
+		"\U0001F1E9\U0001F1EA\U0001F1EB\U0001F1F7",
+		"\U0001F1E9\U0001F1EA\U0001F1EB",
+		"\U0001F1E9\U0001F1EA\u0301 x",
+	} {
+		var expected []string
+		str, state := original, -1
+		for len(str) > 0 {
+			var cluster string
+			cluster, str, _, state = StepString(str, state)
+			expected = append(expected, cluster)
+		}
+
+		for split := range original {
+			if split == 0 {
+				continue
+			}
+			var (
+				clusters []string
+				pending  []byte
+			)
+			state := -1
+			for _, chunk := range []string{original[:split], original[split:]} {
+				b := append(pending, chunk...)
+				for len(b) > 0 {
+					var cluster []byte
+					pendingState := state
+					cluster, b, _, state = Step(b, state)
+					if len(b) == 0 {
+						pending = cluster // The next chunk may continue it.
+						state = pendingState
+						break
+					}
+					clusters = append(clusters, string(cluster))
+				}
+			}
+			if len(pending) > 0 {
+				clusters = append(clusters, string(pending))
+			}
+			if strings.Join(clusters, "|") != strings.Join(expected, "|") {
+				t.Errorf("%q split at %d: got clusters %q, expected %q", original, split, clusters, expected)
+			}
+		}
+	}
+}
+
+// Test that word boundaries are not affected by splitting the text into chunks
+// if the last MaxLookahead runes of each chunk are held back.
+func TestStepChunkedWords(t *testing.T) {
+	for _, original := range []string{
+		"a.b",
+		"Hello, world. It's 3.14 and 1,000.",
+		"e\u0301.\u0301f 'g' \U0001F1E9\U0001F1EA",
+	} {
+		var (
+			expected []string
+			word     string
+		)
+		str, state := original, -1
+		for len(str) > 0 {
+			var (
+				cluster    string
+				boundaries int
+			)
+			cluster, str, boundaries, state = StepString(str, state)
+			word += cluster
+			if boundaries&MaskWord != 0 {
+				expected = append(expected, word)
+				word = ""
+			}
+		}
+
+		for split := range original {
+			if split == 0 {
+				continue
+			}
+			var (
+				words   []string
+				word    []byte
+				pending []byte
+			)
+			state := -1
+			for i, chunk := range []string{original[:split], original[split:]} {
+				b := append(pending, chunk...)
+				for len(b) > 0 {
+					cluster, rest, boundaries, newState := Step(b, state)
+					if i == 0 && utf8.RuneCount(rest) <= MaxLookahead {
+						pending = b // The next chunk may change the boundaries.
+						break
+					}
+					word = append(word, cluster...)
+					if boundaries&MaskWord != 0 {
+						words = append(words, string(word))
+						word = nil
+					}
+					b, state = rest, newState
+				}
+			}
+			if strings.Join(words, "|") != strings.Join(expected, "|") {
+				t.Errorf("%q split at %d: got words %q, expected %q", original, split, words, expected)
+			}
+		}
+	}
+}
+
 // Benchmark the use of the [Step] function.
 func BenchmarkStepBytes(b *testing.B) {
 	for i := 0; i < b.N; i++ {
@@ -484,6 +855,63 @@ func BenchmarkStepString(b *testing.B) {
 	}
 }
 
+// Benchmark the Step function for different scripts.
+func BenchmarkStep(b *testing.B) {
+	for _, corpus := range benchmarkCorpora {
+		text := []byte(corpus.text)
+		b.Run(corpus.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(text)))
+			for i := 0; i < b.N; i++ {
+				var boundaries int
+				state := -1
+				str := text
+				for len(str) > 0 {
+					_, str, boundaries, state = Step(str, state)
+					resultCount += boundaries
+				}
+			}
+		})
+	}
+}
+
+// Test that the functions which are documented to make no allocations don't.
+func TestStepAllocations(t *testing.T) {
+	for _, corpus := range benchmarkCorpora {
+		b := []byte(corpus.text)
+		for name, f := range map[string]func(){
+			"Step": func() {
+				str, state := b, -1
+				for len(str) > 0 {
+					_, str, _, state = Step(str, state)
+				}
+			},
+			"StepString": func() {
+				str, state := corpus.text, -1
+				for len(str) > 0 {
+					_, str, _, state = StepString(str, state)
+				}
+			},
+			"FirstGraphemeClusterInString": func() {
+				str, state := corpus.text, -1
+				for len(str) > 0 {
+					_, str, _, state = FirstGraphemeClusterInString(str, state)
+				}
+			},
+			"GraphemeClusterCount": func() {
+				resultCount = GraphemeClusterCount(corpus.text)
+			},
+			"StringWidth": func() {
+				resultCount = StringWidth(corpus.text)
+			},
+		} {
+			if allocs := testing.AllocsPerRun(10, f); allocs != 0 {
+				t.Errorf("%s: %s made %v allocations, expected none", corpus.name, name, allocs)
+			}
+		}
+	}
+}
+
 // Fuzz the StepString function.
 func FuzzStepString(f *testing.F) {
 	for _, tc := range graphemeBreakTestCases {
@@ -528,3 +956,137 @@ func FuzzStepString(f *testing.F) {
 		// https://github.com/scalecode-solutions/runeseg/issues
 	})
 }
+
+// segmentFuncs are the functions which split text into segments, wrapped to
+// return the length of the first segment and the new state. They are used to
+// check that the functions always make progress, even on invalid UTF-8.
+var segmentFuncs = map[string]func(b []byte, state int) (length, newState int){
+	"Step": func(b []byte, state int) (int, int) {
+		c, _, _, state := Step(b, state)
+		return len(c), state
+	},
+	"StepString": func(b []byte, state int) (int, int) {
+		c, _, _, state := StepString(string(b), state)
+		return len(c), state
+	},
+	"StepWithOptions": func(b []byte, state int) (int, int) {
+		c, _, _, state := StepWithOptions(b, state, Options{AmbiguousWidth: 2, TabWidth: 4, ParagraphBreaks: true, NumberedLists: true})
+		return len(c), state
+	},
+	"LineBreakOptions.Step": func(b []byte, state int) (int, int) {
+		c, _, _, state := LineBreakOptions{BreakBeforeSpaces: true, KeepBrackets: true, NoBreakBefore: "\uff01", NoBreakAfter: "\uff03"}.Step(b, state)
+		return len(c), state
+	},
+	"FirstGraphemeCluster": func(b []byte, state int) (int, int) {
+		c, _, _, state := FirstGraphemeCluster(b, state)
+		return len(c), state
+	},
+	"FirstGraphemeClusterInString": func(b []byte, state int) (int, int) {
+		c, _, _, state := FirstGraphemeClusterInString(string(b), state)
+		return len(c), state
+	},
+	"FirstWord": func(b []byte, state int) (int, int) {
+		c, _, state := FirstWord(b, state)
+		return len(c), state
+	},
+	"FirstWordInString": func(b []byte, state int) (int, int) {
+		c, _, state := FirstWordInString(string(b), state)
+		return len(c), state
+	},
+	"FirstSentence": func(b []byte, state int) (int, int) {
+		c, _, state := FirstSentence(b, state)
+		return len(c), state
+	},
+	"FirstSentenceInString": func(b []byte, state int) (int, int) {
+		c, _, state := FirstSentenceInString(string(b), state)
+		return len(c), state
+	},
+	"FirstLineSegment": func(b []byte, state int) (int, int) {
+		c, _, _, state := FirstLineSegment(b, state)
+		return len(c), state
+	},
+	"FirstLineSegmentInString": func(b []byte, state int) (int, int) {
+		c, _, _, state := FirstLineSegmentInString(string(b), state)
+		return len(c), state
+	},
+	"FirstLineSegmentContext": func(b []byte, state int) (int, int) {
+		c, _, _, state := FirstLineSegmentContext(b, state)
+		return len(c), state
+	},
+}
+
+// checkProgress checks that all segment functions consume at least one byte
+// per call on the given input and thus terminate. It also checks the
+// Graphemes and WordWrapper iterators.
+func checkProgress(t *testing.T, input []byte) {
+	t.Helper()
+	for name, f := range segmentFuncs {
+		rest, state := input, -1
+		for len(rest) > 0 {
+			var length int
+			length, state = f(rest, state)
+			if length <= 0 || length > len(rest) {
+				t.Errorf("%s(%q) returned a segment of length %d with %d bytes left", name, input, length, len(rest))
+				break
+			}
+			rest = rest[length:]
+		}
+	}
+
+	// The iterators return at least one cluster or line per call, so they
+	// can't be called more often than there are bytes.
+	g := NewGraphemes(string(input))
+	for calls := 0; g.Next(); calls++ {
+		if calls > len(input) {
+			t.Errorf("Graphemes iterator on %q does not terminate", input)
+			break
+		}
+	}
+	w := NewWordWrapper(string(input), 3, WrapOptions{BreakLongTokens: true})
+	for calls := 0; w.Next(); calls++ {
+		if calls > len(input) {
+			t.Errorf("WordWrapper on %q does not terminate", input)
+			break
+		}
+	}
+}
+
+// Test that all segment functions make progress on truncated and invalid
+// UTF-8.
+func TestInvalidUTF8Progress(t *testing.T) {
+	samples := []string{
+		benchmarkMultilingual,
+		"\U0001F468\u200d\U0001F469\u200d\U0001F467 \U0001F1E9\U0001F1EA 1\ufe0f\u20e3",
+		"a\u0301 (b [c]) \u0915\u094d\u0937 \u1100\u1161\u11a8 3.14 \u00a7 5. Next.",
+	}
+	invalid := []string{"\x80", "\xbf", "\xc0", "\xc3", "\xe2\x82", "\xf0\x9f\x98", "\xed\xa0\x80", "\xf4\x90\x80\x80", "\xff", "\x00"}
+	for _, sample := range samples {
+		// Every truncation, which also cuts multi-byte sequences.
+		for end := 0; end <= len(sample); end++ {
+			checkProgress(t, []byte(sample[:end]))
+		}
+
+		// Invalid sequences inserted at every position.
+		for _, bad := range invalid {
+			for pos := 0; pos <= len(sample); pos += 7 {
+				checkProgress(t, []byte(sample[:pos]+bad+sample[pos:]))
+			}
+		}
+	}
+
+	// Invalid sequences only.
+	checkProgress(t, []byte(strings.Join(invalid, "")))
+	checkProgress(t, []byte(strings.Repeat("\xe2\x82", 10)))
+}
+
+// Fuzz all segment functions to check that they make progress.
+func FuzzSegmentProgress(f *testing.F) {
+	f.Add([]byte(benchmarkMultilingual))
+	f.Add([]byte("a\xe2\x82b\xffc\xf0\x9f"))
+	for _, tc := range lineBreakTestCases {
+		f.Add([]byte(tc.original))
+	}
+	f.Fuzz(func(t *testing.T, input []byte) {
+		checkProgress(t, input)
+	})
+}
@@ -0,0 +1,164 @@
+package runeseg
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// StripOptions specifies which characters are kept by
+// [StripOptions.StripInvisible]. The zero value removes all invisible
+// characters, including tabs and newlines.
+type StripOptions struct {
+	// KeepNewlines keeps carriage returns (U+000D) and line feeds (U+000A).
+	KeepNewlines bool
+
+	// KeepTabs keeps horizontal tabs (U+0009).
+	KeepTabs bool
+}
+
+// StripInvisible is like [StripOptions.StripInvisible] with the default
+// options, i.e. tabs and newlines are removed, too.
+func StripInvisible(s string) string {
+	return StripOptions{}.StripInvisible(s)
+}
+
+// StripInvisible removes invisible characters from the given string, for
+// example to sanitize user names before displaying them. The following
+// characters are removed:
+//
+//   - Characters with the grapheme cluster break property Control, CR, or LF.
+//     These include control characters (C0, C1), zero width space (U+200B),
+//     the line and paragraph separators (U+2028, U+2029), soft hyphens
+//     (U+00AD), byte order marks (U+FEFF), as well as all bidirectional
+//     formatting characters, i.e. the embeddings and overrides (U+202A to
+//     U+202E), the isolates (U+2066 to U+2069), and the LRM, RLM, and ALM
+//     marks. Tabs and newlines may be kept via the options.
+//   - Zero width non-joiners (U+200C).
+//   - Zero width joiners (U+200D) unless they join two pictographs of an
+//     emoji ZWJ sequence such as "👨‍👩‍👧", i.e. unless they are part of the
+//     same grapheme cluster as an Extended_Pictographic base and the
+//     Extended_Pictographic character following it.
+//
+// All other characters, in particular combining marks and variation
+// selectors, are kept. If nothing is removed, the original string is returned.
+func (o StripOptions) StripInvisible(s string) string {
+	var (
+		b       strings.Builder
+		cluster string
+		copied  int // The number of bytes of "s" which were already processed.
+	)
+	str, state, offset := s, -1, 0
+	for len(str) > 0 {
+		cluster, str, _, state = FirstGraphemeClusterInString(str, state)
+		firstProp := propertyGraphemes(firstRune(cluster))
+		for pos, r := range cluster {
+			if o.keep(r, firstProp, cluster[pos:]) {
+				continue
+			}
+
+			// Remove this rune.
+			if b.Cap() == 0 {
+				b.Grow(len(s))
+			}
+			_, length := utf8.DecodeRuneInString(cluster[pos:])
+			b.WriteString(s[copied : offset+pos])
+			copied = offset + pos + length
+		}
+		offset += len(cluster)
+	}
+	if copied == 0 {
+		return s
+	}
+	b.WriteString(s[copied:])
+	return b.String()
+}
+
+// IsDefaultIgnorable returns true if the given rune has the Unicode property
+// Default_Ignorable_Code_Point. These code points are not rendered visibly,
+// not even as a replacement glyph such as a box, by fonts which don't support
+// them. They include the soft hyphen (U+00AD), the zero width space, joiner,
+// and non-joiner (U+200B to U+200D), the bidirectional formatting characters,
+// the byte order mark (U+FEFF), the variation selectors, the tag characters
+// (U+E0000 to U+E007F), the Hangul fillers, and a few reserved code points.
+//
+// With the exception of U+115F HANGUL CHOSEONG FILLER, which starts a
+// conjoining Hangul syllable with a width of 2, these code points have a
+// monospace width of 0 (see [StringWidth]).
+func IsDefaultIgnorable(r rune) bool {
+	return property(defaultIgnorableCodePoints, r) == prDefaultIgnorable
+}
+
+// IsWhitespaceCluster returns true if the given grapheme cluster consists
+// entirely of white space characters, i.e. characters with the Unicode
+// White_Space property. These include the ASCII spaces, tabs, and line
+// breaks, the no-break spaces (U+00A0, U+2007, U+202F), the other space
+// separators such as the ideographic space (U+3000), and the line and
+// paragraph separators (U+2028, U+2029). Clusters in which a space is extended
+// by a combining mark are not white space. False is returned for an empty
+// string.
+func IsWhitespaceCluster(cluster string) bool {
+	if cluster == "" {
+		return false
+	}
+	for _, r := range cluster {
+		switch property(sentenceBreakCodePoints, r) {
+		case prSp, prCR, prLF, prSep:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// TrimSpaceGraphemes returns the given string with all leading and trailing
+// white space grapheme clusters removed (see [IsWhitespaceCluster]). Unlike
+// [strings.TrimSpace], it does not remove a space which is extended by a
+// combining mark following it.
+func TrimSpaceGraphemes(s string) string {
+	var (
+		start, end = -1, 0
+		cluster    string
+	)
+	str, state, offset := s, -1, 0
+	for len(str) > 0 {
+		cluster, str, _, state = FirstGraphemeClusterInString(str, state)
+		offset += len(cluster)
+		if !IsWhitespaceCluster(cluster) {
+			if start < 0 {
+				start = offset - len(cluster)
+			}
+			end = offset
+		}
+	}
+	if start < 0 {
+		return ""
+	}
+	return s[start:end]
+}
+
+// keep returns whether the rune "r" should be kept by
+// [StripOptions.StripInvisible]. The grapheme property of the first rune of
+// the rune's grapheme cluster and the rest of the cluster starting with "r"
+// are also provided.
+func (o StripOptions) keep(r rune, firstProp int, rest string) bool {
+	switch propertyGraphemes(r) {
+	case prControl:
+		return r == '\t' && o.KeepTabs
+	case prCR, prLF:
+		return o.KeepNewlines
+	case prZWJ:
+		if firstProp != prExtendedPictographic {
+			return false
+		}
+		_, length := utf8.DecodeRuneInString(rest)
+		return propertyGraphemes(firstRune(rest[length:])) == prExtendedPictographic
+	}
+	return r != 0x200c // ZWNJ.
+}
+
+// firstRune returns the first rune of the given string or utf8.RuneError if
+// the string is empty.
+func firstRune(s string) rune {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r
+}
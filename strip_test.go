@@ -0,0 +1,139 @@
+package runeseg
+
+import "testing"
+
+// Test the removal of invisible characters.
+func TestStripInvisible(t *testing.T) {
+	for _, testCase := range []struct {
+		original, expected, keep string
+	}{
+		{"", "", ""},
+		{"Alice", "Alice", "Alice"},
+		{"Al\u200bice", "Alice", "Alice"},                               // Zero width space.
+		{"Al\u200dice", "Alice", "Alice"},                               // Lone ZWJ.
+		{"\u200dAlice\u200c", "Alice", "Alice"},                         // ZWJ and ZWNJ.
+		{"\u202eecilA\u202c", "ecilA", "ecilA"},                         // Bidi override.
+		{"\u2067Alice\u2069\u200f", "Alice", "Alice"},                   // Bidi isolate and RLM.
+		{"Al\x00i\x1bce\u0085", "Alice", "Alice"},                       // Control characters.
+		{"Alice\tBob\r\nCarol", "AliceBobCarol", "Alice\tBob\r\nCarol"}, // Tabs and newlines.
+		{"Zoe\u0308", "Zoe\u0308", "Zoe\u0308"},                         // Combining mark.
+		{"\u2764\ufe0f", "\u2764\ufe0f", "\u2764\ufe0f"},                // Variation selector.
+		{"\U0001F468\u200d\U0001F469", "\U0001F468\u200d\U0001F469", "\U0001F468\u200d\U0001F469"}, // Emoji ZWJ sequence.
+		{"\U0001F468\u200d\U0001F469\u200d\U0001F467\u200d\U0001F466", "\U0001F468\u200d\U0001F469\u200d\U0001F467\u200d\U0001F466", "\U0001F468\u200d\U0001F469\u200d\U0001F467\u200d\U0001F466"},
+		{"\U0001F3F3\ufe0f\u200d\U0001F308", "\U0001F3F3\ufe0f\u200d\U0001F308", "\U0001F3F3\ufe0f\u200d\U0001F308"}, // ZWJ after a variation selector.
+		{"\U0001F44D\u200d", "\U0001F44D", "\U0001F44D"},                                                             // Trailing ZWJ.
+		{"\U0001F44D\u200d\u200dX", "\U0001F44DX", "\U0001F44DX"},                                                    // Double ZWJ.
+		{"\u0915\u094d\u200d\u0937", "\u0915\u094d\u0937", "\u0915\u094d\u0937"},                                     // ZWJ in a conjunct.
+		{"\xffA\u200b", "\xffA", "\xffA"}, // Invalid UTF-8 is kept.
+	} {
+		if actual := StripInvisible(testCase.original); actual != testCase.expected {
+			t.Errorf("StripInvisible(%q) is %q, expected %q", testCase.original, actual, testCase.expected)
+		}
+		if actual := (StripOptions{KeepNewlines: true, KeepTabs: true}).StripInvisible(testCase.original); actual != testCase.keep {
+			t.Errorf("StripInvisible(%q) keeping tabs and newlines is %q, expected %q", testCase.original, actual, testCase.keep)
+		}
+	}
+}
+
+// Test the IsDefaultIgnorable function and the width of default ignorable
+// code points.
+func TestIsDefaultIgnorable(t *testing.T) {
+	for _, r := range []rune{
+		0x00ad,         // SOFT HYPHEN
+		0x034f,         // COMBINING GRAPHEME JOINER
+		0x061c,         // ARABIC LETTER MARK
+		0x115f, 0x1160, // Hangul choseong and jungseong fillers.
+		0x17b4, 0x17b5, // Khmer inherent vowels.
+		0x180b, 0x180e, // Mongolian variation selector and vowel separator.
+		0x180f,         // MONGOLIAN FREE VARIATION SELECTOR FOUR
+		0x200b, 0x200f, // ZERO WIDTH SPACE to RIGHT-TO-LEFT MARK
+		0x202a, 0x202e, // Bidirectional embeddings and overrides.
+		0x2060, 0x2065, // WORD JOINER and a reserved code point.
+		0x2066, 0x206f, // Bidirectional isolates to NOMINAL DIGIT SHAPES.
+		0x3164,         // HANGUL FILLER
+		0xfe00, 0xfe0f, // Variation selectors.
+		0xfeff,         // ZERO WIDTH NO-BREAK SPACE
+		0xffa0,         // HALFWIDTH HANGUL FILLER
+		0xfff0, 0xfff8, // Reserved.
+		0x1bca0, 0x1bca3, // Shorthand format controls.
+		0x1d173, 0x1d17a, // Musical symbol formatting.
+		0xe0000, 0xe0001, // Reserved and LANGUAGE TAG.
+		0xe0020, 0xe007f, // Tag characters.
+		0xe0100, 0xe01ef, // Variation selectors supplement.
+		0xe0fff, // Reserved.
+	} {
+		if !IsDefaultIgnorable(r) {
+			t.Errorf("%U is not default ignorable", r)
+		}
+		expected := 0
+		if r == 0x115f {
+			expected = 2 // Starts a Hangul syllable.
+		}
+		if w := StringWidth(string(r)); w != expected {
+			t.Errorf("%U has width %d, expected %d", r, w, expected)
+		}
+	}
+	for _, r := range []rune{
+		'a', ' ', '\t', 0x00a0, 0x00ac, 0x0600, 0x115e, 0x1161, 0x2028,
+		0x2070, 0x3000, 0xfff9, 0x13430, 0xe1000, 0x10ffff, -1,
+	} {
+		if IsDefaultIgnorable(r) {
+			t.Errorf("%U is default ignorable", r)
+		}
+	}
+	if w := StringWidth("\u115f\u1161"); w != 2 {
+		t.Errorf("Hangul syllable with choseong filler has width %d, expected 2", w)
+	}
+}
+
+// Test the classification of white space clusters.
+func TestIsWhitespaceCluster(t *testing.T) {
+	for _, testCase := range []struct {
+		cluster  string
+		expected bool
+	}{
+		{"", false},
+		{" ", true},
+		{"\t", true},
+		{"\r\n", true},
+		{"\u0085", true}, // Next line.
+		{"\u00a0", true}, // No-break space.
+		{"\u2007", true}, // Figure space.
+		{"\u202f", true}, // Narrow no-break space.
+		{"\u3000", true}, // Ideographic space.
+		{"\u2028", true}, // Line separator.
+		{"\u2029", true}, // Paragraph separator.
+		{"\u1680", true}, // Ogham space mark.
+		{"a", false},
+		{"\u200b", false},       // Zero width space is not white space.
+		{"\ufeff", false},       // Byte order mark.
+		{" \u0301", false},      // Space with a combining mark.
+		{"\u3000\u3099", false}, // Ideographic space with a combining mark.
+	} {
+		if actual := IsWhitespaceCluster(testCase.cluster); actual != testCase.expected {
+			t.Errorf("IsWhitespaceCluster(%q) is %v, expected %v", testCase.cluster, actual, testCase.expected)
+		}
+	}
+}
+
+// Test the trimming of white space clusters.
+func TestTrimSpaceGraphemes(t *testing.T) {
+	for _, testCase := range []struct {
+		original, expected string
+	}{
+		{"", ""},
+		{"   ", ""},
+		{"abc", "abc"},
+		{"  a b  ", "a b"},
+		{"\u3000\u3000\u65e5\u672c\u3000\u8a9e\u3000", "\u65e5\u672c\u3000\u8a9e"}, // Ideographic spaces.
+		{"\u00a0\u00a0Alice\u00a0", "Alice"},                                       // No-break spaces.
+		{"\u2007\u202f42\u2009\r\n", "42"},
+		{"\t\u3000\u00a0x\u00a0\u3000\t", "x"},
+		{" \u0301x ", " \u0301x"}, // A space with a combining mark is not trimmed.
+		{"\u200bx\u200b", "\u200bx\u200b"},
+	} {
+		if actual := TrimSpaceGraphemes(testCase.original); actual != testCase.expected {
+			t.Errorf("TrimSpaceGraphemes(%q) is %q, expected %q", testCase.original, actual, testCase.expected)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package runeseg
+
+// UnicodeVersion is the version of the Unicode Standard this package conforms
+// to. The character property tables as well as the rules for grapheme cluster,
+// word, sentence, and line boundaries follow this version.
+const UnicodeVersion = "17.0.0"
+
+// TableInfo describes one of the character property tables used by this
+// package.
+type TableInfo struct {
+	// Name is the name of the Unicode Character Database file the table was
+	// generated from, e.g. "LineBreak".
+	Name string
+
+	// UnicodeVersion is the Unicode version of that file.
+	UnicodeVersion string
+
+	// Date is the date the table was generated, in the format "2006-01-02".
+	Date string
+}
+
+// Tables returns information about the character property tables compiled into
+// this package.
+func Tables() []TableInfo {
+	return []TableInfo{
+		{"GraphemeBreakProperty", graphemeCodePointsVersion, graphemeCodePointsDate},
+		{"WordBreakProperty", wordBreakCodePointsVersion, wordBreakCodePointsDate},
+		{"SentenceBreakProperty", sentenceBreakCodePointsVersion, sentenceBreakCodePointsDate},
+		{"LineBreak", lineBreakCodePointsVersion, lineBreakCodePointsDate},
+		{"EastAsianWidth", eastAsianWidthVersion, eastAsianWidthDate},
+		{"emoji-data", emojiPresentationVersion, emojiPresentationDate},
+		{"DerivedCoreProperties", incbCodePointsVersion, incbCodePointsDate},
+		{"DerivedCoreProperties", defaultIgnorableCodePointsVersion, defaultIgnorableCodePointsDate},
+	}
+}
+
+// Version returns the Unicode version of the character property tables
+// compiled into this package. Unlike [UnicodeVersion], the result is derived
+// from the tables themselves. If the tables were generated from different
+// Unicode versions, an empty string is returned.
+func Version() string {
+	var version string
+	for _, table := range Tables() {
+		if version == "" {
+			version = table.UnicodeVersion
+		} else if table.UnicodeVersion != version {
+			return ""
+		}
+	}
+	return version
+}
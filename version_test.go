@@ -0,0 +1,25 @@
+package runeseg
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+// Test that the Unicode version is well-formed and matches the tables.
+func TestVersion(t *testing.T) {
+	if !regexp.MustCompile(`^\d+\.\d+\.\d+$`).MatchString(UnicodeVersion) {
+		t.Errorf("UnicodeVersion %q is not of the form major.minor.update", UnicodeVersion)
+	}
+	if v := Version(); v != UnicodeVersion {
+		t.Errorf("Version() returned %q, expected %q", v, UnicodeVersion)
+	}
+	for _, table := range Tables() {
+		if table.UnicodeVersion != UnicodeVersion {
+			t.Errorf("Table %s has Unicode version %q, expected %q", table.Name, table.UnicodeVersion, UnicodeVersion)
+		}
+		if _, err := time.Parse("2006-01-02", table.Date); err != nil {
+			t.Errorf("Table %s has invalid date %q: %v", table.Name, table.Date, err)
+		}
+	}
+}
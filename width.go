@@ -1,25 +1,38 @@
 package runeseg
 
+import (
+	"strings"
+	"unicode/utf8"
+)
+
 // EastAsianAmbiguousWidth specifies the monospace width for East Asian
 // characters classified as Ambiguous (width class "A" in Unicode). The default
 // is 1, but some fonts (particularly in East Asian locales) render them with a
 // width of 2. Adjust this value based on your target environment.
 var EastAsianAmbiguousWidth = 1
 
-// runeWidth returns the monospace width for the given rune. The provided
-// grapheme property is a value mapped by the [graphemeCodePoints] table.
+// runeWidth returns the monospace width for the given rune, decoded from "size"
+// bytes. The provided grapheme property is a value mapped by the
+// [graphemeCodePoints] table.
 //
 // Every rune has a width of 1, except for runes with the following properties
 // (evaluated in this order):
 //
 //   - Control, CR, LF, Extend, ZWJ: Width of 0
+//   - \u1160, HANGUL JUNGSEONG FILLER, \u3164, HANGUL FILLER, and \uffa0,
+//     HALFWIDTH HANGUL FILLER: Width of 0 (these are the default ignorable
+//     code points not covered by the grapheme properties above, except for
+//     \u115f, HANGUL CHOSEONG FILLER, which starts a wide Hangul syllable)
 //   - \u2e3a, TWO-EM DASH: Width of 3
 //   - \u2e3b, THREE-EM DASH: Width of 4
+//   - Invalid UTF-8 bytes, decoded as \ufffd, REPLACEMENT CHARACTER, with a
+//     size of 1: Width of 1, regardless of [EastAsianAmbiguousWidth]. An
+//     actual replacement character is East Asian Ambiguous.
 //   - East-Asian width Fullwidth and Wide: Width of 2 (Ambiguous and Neutral
 //     have a width of 1)
 //   - Regional Indicator: Width of 2
 //   - Extended Pictographic: Width of 2, unless Emoji Presentation is "No".
-func runeWidth(r rune, graphemeProperty int) int {
+func runeWidth(r rune, size, graphemeProperty int) int {
 	switch graphemeProperty {
 	case prControl, prCR, prLF, prExtend, prZWJ:
 		return 0
@@ -33,10 +46,16 @@ func runeWidth(r rune, graphemeProperty int) int {
 	}
 
 	switch r {
+	case 0x1160, 0x3164, 0xffa0:
+		return 0
 	case 0x2e3a:
 		return 3
 	case 0x2e3b:
 		return 4
+	case utf8.RuneError:
+		if size <= 1 {
+			return 1 // Invalid UTF-8.
+		}
 	}
 
 	switch propertyEastAsianWidth(r) {
@@ -49,11 +68,24 @@ func runeWidth(r rune, graphemeProperty int) int {
 	return 1
 }
 
+// Valid reports whether the given string consists entirely of valid UTF-8
+// encoded runes. It is equivalent to [utf8.ValidString]. Invalid strings may
+// still be passed to all functions of this package, see the package
+// documentation for how invalid bytes are handled.
+func Valid(s string) bool {
+	return utf8.ValidString(s)
+}
+
 // StringWidth returns the monospace width for the given string, that is, the
 // number of same-size terminal cells to be occupied by the string. This is
 // useful for aligning text in terminal applications and calculating display
 // widths for Unicode strings containing wide characters, emoji, and combining
 // marks.
+//
+// The width of each grapheme cluster is determined independently of its
+// neighbors. Contextual shaping is not taken into account, e.g. joined Arabic
+// letters have a width of 1 each, including the two letters of the LAM ALEF
+// ligature, which some terminals render in a single cell.
 func StringWidth(s string) (width int) {
 	state := -1
 	for len(s) > 0 {
@@ -63,3 +95,198 @@ func StringWidth(s string) (width int) {
 	}
 	return
 }
+
+// IsMonospaceSimple returns true if every grapheme cluster in the given string
+// consists of exactly one code point with a monospace width of 1. Strings
+// containing combining marks, wide characters, emoji, or control characters
+// (including tabs and newlines) are not simple. For simple strings, the number
+// of bytes (if the string is also ASCII), runes, grapheme clusters, and
+// character cells are interchangeable, so callers may skip the full width
+// calculation. The empty string is simple.
+//
+// The string is scanned from the start and the function returns as soon as
+// the first non-simple cluster is found.
+func IsMonospaceSimple(s string) bool {
+	state := -1
+	for len(s) > 0 {
+		// Fast track printable ASCII which is not followed by a potentially
+		// extending code point.
+		if c := s[0]; c >= 0x20 && c <= 0x7e && (len(s) == 1 || s[1] < utf8.RuneSelf) {
+			s = s[1:]
+			state = -1
+			continue
+		}
+
+		var (
+			cluster string
+			width   int
+		)
+		cluster, s, width, state = FirstGraphemeClusterInString(s, state)
+		if width != 1 || utf8.RuneCountInString(cluster) != 1 {
+			return false
+		}
+		r, _ := utf8.DecodeRuneInString(cluster)
+		switch propertyGraphemes(r) {
+		case prExtendedPictographic, prRegionalIndicator:
+			return false
+		}
+	}
+	return true
+}
+
+// ClusterWidth returns the monospace width of the given string which is
+// assumed to be a single grapheme cluster, for example one returned by
+// [Graphemes.Str] or [FirstGraphemeClusterInString]. The width is calculated
+// the same way as in [Step], i.e. the first code point of the cluster
+// determines the cluster's width: Emoji sequences (including ZWJ sequences and
+// keycaps) take the width of their base, adjusted by variation selectors 15
+// and 16, flags have a width of 2, and combining marks don't add any width.
+//
+// If the string contains more than one grapheme cluster, the result is
+// undefined. Use [StringWidth] for arbitrary strings.
+func ClusterWidth(cluster string) int {
+	if len(cluster) == 0 {
+		return 0
+	}
+	r, length := utf8.DecodeRuneInString(cluster)
+	firstProp := propertyGraphemes(r)
+	width := runeWidth(r, length, firstProp)
+	for length < len(cluster) {
+		r, l := utf8.DecodeRuneInString(cluster[length:])
+		if firstProp == prExtendedPictographic {
+			switch r {
+			case vs15:
+				width = 1
+			case vs16:
+				width = 2
+			}
+		} else if firstProp != prRegionalIndicator && firstProp != prL {
+			width += runeWidth(r, l, propertyGraphemes(r))
+		}
+		length += l
+	}
+	return width
+}
+
+// RepeatWidth returns the string "s" repeated until the result reaches the
+// given monospace width, e.g. to draw separator lines with box-drawing
+// characters. The result never exceeds the width. It ends at a grapheme
+// cluster boundary, so the last repetition of "s" may be incomplete and the
+// result may be narrower than targetWidth, e.g. when repeating a wide
+// character to fill an odd width:
+//
+//	RepeatWidth("-=", 5) // "-=-=-"
+//	RepeatWidth("─", 5)  // "─────"
+//	RepeatWidth("ー", 5) // "ーー" (width 4)
+//
+// The widths are determined for the repeated string as a whole, i.e. grapheme
+// clusters spanning the seams between repetitions (such as two regional
+// indicators forming a flag) are accounted for. If all repetitions form a
+// single grapheme cluster, as with Hangul leading consonants (U+1100 etc.), "s"
+// is returned once if it fits. An empty string is returned if "s" has a width
+// of 0 or if targetWidth is not positive.
+func RepeatWidth(s string, targetWidth int) string {
+	width := StringWidth(s)
+	if width == 0 || targetWidth <= 0 {
+		return ""
+	}
+	for count := targetWidth/width + 2; ; count *= 2 {
+		repeated := strings.Repeat(s, count)
+		str, state := repeated, -1
+		var total int
+		for len(str) > 0 {
+			var (
+				cluster string
+				w       int
+			)
+			cluster, str, w, state = FirstGraphemeClusterInString(str, state)
+			if len(str) == 0 {
+				if len(cluster) == len(repeated) {
+					// The repetitions never end the cluster, so more of them
+					// won't help.
+					if width > targetWidth {
+						return ""
+					}
+					return s
+				}
+				break // The last cluster may extend into the next repetition.
+			}
+			if total+w > targetWidth {
+				return repeated[:len(repeated)-len(str)-len(cluster)]
+			}
+			total += w
+		}
+	}
+}
+
+// ByteOffsetForColumn returns the byte offset in the given string of the
+// grapheme cluster which occupies the given zero-based monospace column, e.g.
+// to map a terminal cursor position to a position in the text. All columns
+// occupied by a wide cluster map to the start of that cluster. Clusters
+// without width, such as control characters, don't occupy any columns and are
+// skipped. Negative columns return 0 and columns at or beyond the width of the
+// string (see [StringWidth]) return the length of the string.
+func ByteOffsetForColumn(s string, column int) int {
+	if column < 0 {
+		return 0
+	}
+	var (
+		offset, width int
+		cluster       string
+	)
+	str, state := s, -1
+	for len(str) > 0 {
+		var w int
+		cluster, str, w, state = FirstGraphemeClusterInString(str, state)
+		if column < width+w {
+			return offset
+		}
+		width += w
+		offset += len(cluster)
+	}
+	return len(s)
+}
+
+// ColumnForByteOffset returns the zero-based monospace column at which the
+// grapheme cluster containing the given byte offset begins. It is the inverse
+// of [ByteOffsetForColumn], e.g. to map a position in the text, where edits
+// happen, to the terminal column where the cursor is drawn. Offsets inside a
+// grapheme cluster are snapped to the start of that cluster. Negative offsets
+// return 0 and offsets at or beyond the length of the string return the width
+// of the string (see [StringWidth]).
+func ColumnForByteOffset(s string, byteOffset int) int {
+	var (
+		offset, width int
+		cluster       string
+	)
+	str, state := s, -1
+	for len(str) > 0 {
+		var w int
+		cluster, str, w, state = FirstGraphemeClusterInString(str, state)
+		offset += len(cluster)
+		if byteOffset < offset {
+			return width
+		}
+		width += w
+	}
+	return width
+}
+
+// MaxLineWidth returns the monospace width of the widest line of the given
+// string, e.g. to determine the size of a text box. The string is split into
+// lines at mandatory line breaks (see [LineMustBreak]), such as newline
+// characters, and the width of each line is calculated as with [StringWidth].
+// Trailing spaces count towards the width of a line. The line break characters
+// themselves have no width. To expand tabs, use [Options.MaxLineWidth].
+func MaxLineWidth(s string) int {
+	return Options{}.MaxLineWidth(s)
+}
+
+// Fits returns true if no line of the given string is wider than the given
+// number of monospace columns, i.e. if wrapping the string at that width would
+// not change it. Lines are measured as with [MaxLineWidth], but the string is
+// only processed up to the first line which doesn't fit. To apply width
+// options, use [Options.Fits].
+func Fits(s string, width int) bool {
+	return Options{}.Fits(s, width)
+}
@@ -24,6 +24,8 @@ var widthTestCases = []struct {
 	{"\x0f", 0},
 	{"\u0300", 0}, // Extend
 	{"\u200d", 0}, // ZERO WIDTH JOINER
+	{"\u00ad", 0}, // SOFT HYPHEN
+	{"co\u00adoperate", 9},
 	{"a", 1},
 	{"\u1b05", 1},     // N
 	{"\u2985", 1},     // Na
@@ -33,9 +35,23 @@ var widthTestCases = []struct {
 	{"\uff01", 2},     // F
 	{"\u2e3a", 3},     // TWO-EM DASH
 	{"\u2e3b", 4},     // THREE-EM DASH
+	{"\u3000", 2},     // IDEOGRAPHIC SPACE (F)
+	{"\u3042\u3000\u3044", 6},
 	{"\u00a9", 1},     // Extended Pictographic (Emoji Presentation = No)
 	{"\U0001F60A", 2}, // Extended Pictographic (Emoji Presentation = Yes)
 	{"\U0001F1E6", 2}, // Regional Indicator
+	{"\U0001F3F4", 2}, // WAVING BLACK FLAG (Emoji Presentation = Yes)
+	{"\u231b", 2},     // HOURGLASS (Emoji Presentation = Yes)
+	{"\u2702", 1},     // BLACK SCISSORS (Emoji Presentation = No)
+	{"\u2764", 1},     // HEAVY BLACK HEART (Emoji Presentation = No)
+	{"\u263a", 1},     // WHITE SMILING FACE (Emoji Presentation = No)
+	{"\u260e", 1},     // BLACK TELEPHONE (Emoji Presentation = No)
+	{"\u260e\ufe0f", 2},
+	{"\u260e\ufe0e", 1},
+	{"\u2702\ufe0f", 2},
+	{"\u2764\ufe0f", 2},
+	{"\u231b\ufe0e", 1},
+	{"\U0001F3F4\u200d\u2620\ufe0f", 2}, // Pirate flag
 	{"\u061c\u061c", 0},
 	{"\u061c\u000a", 0},
 	{"\u061c\u000d", 0},
@@ -494,3 +510,421 @@ func TestRunesWidth(t *testing.T) {
 		}
 	}
 }
+
+// Test the IsMonospaceSimple function.
+func TestIsMonospaceSimple(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected bool
+	}{
+		{"", true},
+		{"Hello, world!", true},
+		{"Gr\u00fc\u00dfe", true}, // Precomposed characters.
+		{"Привет", true},          // Cyrillic.
+		{"世界", false},             // CJK (wide).
+		{"Hello世界", false},        // Mixed.
+		{"mo\u0308p", false},      // Combining diaeresis.
+		{"e\u0301", false},        // Combining acute accent.
+		{"a\u0301b", false},       // Combining mark after ASCII.
+		{"\u00a9", false},         // Text presentation emoji.
+		{"🙂", false},              // Emoji.
+		{"🇩🇪", false},             // Flag.
+		{"a\tb", false},           // Tab.
+		{"line\n", false},         // Newline.
+		{"क्ष", false},            // Indic conjunct.
+	} {
+		if actual := IsMonospaceSimple(testCase.original); actual != testCase.expected {
+			t.Errorf("IsMonospaceSimple(%q) is %v, expected %v", testCase.original, actual, testCase.expected)
+		}
+	}
+}
+
+// Test the ClusterWidth function against the widths reported by StepString.
+func TestClusterWidth(t *testing.T) {
+	originals := []string{
+		"\U0001f469\u200d\u2764\ufe0f\u200d\U0001f48b\u200d\U0001f469", // ZWJ sequence.
+		"\U0001f3f3\ufe0f\u200d\U0001f308",                             // ZWJ sequence with variation selector.
+		"\U0001f3cb\U0001f3fd\u200d\u2640\ufe0f",                       // Skin tone modifier and ZWJ.
+		"\U0001f468\u200d\U0001f469\u200d\U0001f467\u200d\U0001f466",   // Family.
+		"\U0001f44d\u200d",         // Incomplete ZWJ sequence.
+		"1\ufe0f\u20e3",            // Keycap.
+		"\u263a\ufe0f",             // Emoji presentation selector.
+		"\u231b\ufe0e",             // Text presentation selector.
+		"\U0001f1e9\U0001f1ea",     // Flag.
+		"\u1112\u116f\u11b6",       // Conjoining Jamo.
+		"\u0915\u094d\u0937\u093f", // Indic conjunct.
+		"mo\u0308p",                // Combining mark.
+		"\r\n",                     // CRLF.
+	}
+	for _, testCase := range widthTestCases {
+		originals = append(originals, testCase.original)
+	}
+	for _, original := range originals {
+		str := original
+		state := -1
+		for len(str) > 0 {
+			var (
+				cluster    string
+				boundaries int
+			)
+			cluster, str, boundaries, state = StepString(str, state)
+			if actual, expected := ClusterWidth(cluster), boundaries>>ShiftWidth; actual != expected {
+				t.Errorf("ClusterWidth(%q) is %d, expected %d (from %q)", cluster, actual, expected, original)
+			}
+		}
+	}
+	if w := ClusterWidth(""); w != 0 {
+		t.Errorf("ClusterWidth of empty string is %d, expected 0", w)
+	}
+	if w := ClusterWidth("👨‍👩‍👧‍👦"); w != 2 {
+		t.Errorf("ClusterWidth of family emoji is %d, expected 2", w)
+	}
+}
+
+// Test that combining enclosing marks (general category Me) are absorbed by
+// the preceding base character (GB9) and do not add any width.
+func TestWidthEnclosingMarks(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		clusters []string
+		widths   []int
+	}{
+		{"A\u20dd", []string{"A\u20dd"}, []int{1}},             // Combining enclosing circle.
+		{"A\u20de", []string{"A\u20de"}, []int{1}},             // Combining enclosing square.
+		{"1\u20e3", []string{"1\u20e3"}, []int{1}},             // Combining enclosing keycap.
+		{"1\ufe0f\u20e3", []string{"1\ufe0f\u20e3"}, []int{1}}, // Emoji keycap sequence.
+		{"\u4e16\u20dd", []string{"\u4e16\u20dd"}, []int{2}},   // Wide base.
+		{"x\u20dd\u20de", []string{"x\u20dd\u20de"}, []int{1}}, // Multiple enclosing marks.
+		{"A\u20ddB\u20de", []string{"A\u20dd", "B\u20de"}, []int{1, 1}},
+		{"\u20ddA", []string{"\u20dd", "A"}, []int{0, 1}}, // No base.
+	} {
+		checkClusterWidths(t, testCase.original, testCase.clusters, testCase.widths)
+	}
+	for _, r := range []rune{0x20dd, 0x20de, 0x20e3} {
+		if prop := propertyGraphemes(r); prop != prExtend {
+			t.Errorf("Grapheme property of %U is %d, expected Extend", r, prop)
+		}
+	}
+}
+
+// Test that Extended Pictographic symbols have a width of 2 only if their
+// default presentation is emoji or if they are followed by VS16 (U+FE0F).
+func TestWidthEmojiPresentation(t *testing.T) {
+	for _, testCase := range []struct {
+		r     rune
+		emoji bool // Default emoji presentation.
+	}{
+		{0x260e, false},  // BLACK TELEPHONE
+		{0x2600, false},  // BLACK SUN WITH RAYS
+		{0x2708, false},  // AIRPLANE
+		{0x2122, false},  // TRADE MARK SIGN
+		{0x1f321, false}, // THERMOMETER
+		{0x1f3f3, false}, // WAVING WHITE FLAG
+		{0x231a, true},   // WATCH
+		{0x2614, true},   // UMBRELLA WITH RAIN DROPS
+		{0x26a1, true},   // HIGH VOLTAGE SIGN
+		{0x2705, true},   // WHITE HEAVY CHECK MARK
+		{0x2b50, true},   // WHITE MEDIUM STAR
+		{0x1f600, true},  // GRINNING FACE
+	} {
+		if prop := propertyGraphemes(testCase.r); prop != prExtendedPictographic {
+			t.Errorf("%U is not Extended Pictographic", testCase.r)
+		}
+		if emoji := property(emojiPresentation, testCase.r) == prEmojiPresentation; emoji != testCase.emoji {
+			t.Errorf("%U has Emoji Presentation %t, expected %t", testCase.r, emoji, testCase.emoji)
+		}
+		expected := 1
+		if testCase.emoji {
+			expected = 2
+		}
+		for _, suffix := range []string{"", "\ufe0f", "\ufe0e"} {
+			str := string(testCase.r) + suffix
+			switch suffix {
+			case "\ufe0f":
+				expected = 2
+			case "\ufe0e":
+				expected = 1
+			}
+			if w := StringWidth(str); w != expected {
+				t.Errorf("%q: StringWidth is %d, expected %d", str, w, expected)
+			}
+			if _, _, boundaries, _ := StepString(str, -1); boundaries>>ShiftWidth != expected {
+				t.Errorf("%q: StepString width is %d, expected %d", str, boundaries>>ShiftWidth, expected)
+			}
+		}
+	}
+}
+
+// Test the RepeatWidth function.
+func TestRepeatWidth(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		width    int
+		expected string
+	}{
+		{"\u2500", 10, "\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500\u2500"}, // Box drawing.
+		{"\u2550", 3, "\u2550\u2550\u2550"},                                            // Box drawing, double.
+		{"\u30fc", 10, "\u30fc\u30fc\u30fc\u30fc\u30fc"},                               // Wide.
+		{"\u30fc", 9, "\u30fc\u30fc\u30fc\u30fc"},                                      // Wide, odd width.
+		{"\uff1d", 1, ""},  // Wide character doesn't fit.
+		{"-=", 5, "-=-=-"}, // Partial repetition.
+		{"\u4e16a", 5, "\u4e16a\u4e16"},
+		{"a\u4e16", 5, "a\u4e16a"},                                    // Wide character doesn't fit at the end.
+		{"e\u0301", 3, "e\u0301e\u0301e\u0301"},                       // Combining marks.
+		{"\U0001f1e9", 5, "\U0001f1e9\U0001f1e9\U0001f1e9\U0001f1e9"}, // Regional indicators pair up across repetitions.
+		{"\u1100", 10, "\u1100"},                                      // Leading consonants form a single cluster.
+		{"\u1100", 1, ""},                                             // Single cluster doesn't fit.
+		{"\U0001f468\u200d", 10, "\U0001f468\u200d"},                  // ZWJ sequence never ends.
+		{"a", 0, ""},
+		{"a", -1, ""},
+		{"", 5, ""},
+		{"\u200b", 5, ""}, // Zero width.
+	} {
+		actual := RepeatWidth(testCase.original, testCase.width)
+		if actual != testCase.expected {
+			t.Errorf("RepeatWidth(%q, %d) is %q, expected %q", testCase.original, testCase.width, actual, testCase.expected)
+		}
+		if width := StringWidth(actual); width > testCase.width && testCase.width >= 0 {
+			t.Errorf("RepeatWidth(%q, %d) has width %d", testCase.original, testCase.width, width)
+		}
+	}
+}
+
+// Test the ByteOffsetForColumn function.
+func TestByteOffsetForColumn(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		offsets  []int // Indexed by column, starting at column -1.
+	}{
+		{"", []int{0, 0, 0}},
+		{"a\u3042b", []int{0, 0, 1, 1, 4, 5, 5}},
+		{"\u4e16\u754c", []int{0, 0, 0, 3, 3, 6, 6}},
+		{"e\u0301x", []int{0, 0, 3, 4}},                         // Combining mark.
+		{"\U0001F468\u200d\U0001F469!", []int{0, 0, 0, 11, 12}}, // Emoji ZWJ sequence.
+		{"\u200ba\tb", []int{0, 3, 5, 6}},                       // Clusters without width are skipped.
+	} {
+		for i, expected := range testCase.offsets {
+			column := i - 1
+			if offset := ByteOffsetForColumn(testCase.original, column); offset != expected {
+				t.Errorf("ByteOffsetForColumn(%q, %d) is %d, expected %d", testCase.original, column, offset, expected)
+			}
+		}
+	}
+}
+
+// Test mapping byte offsets to columns.
+func TestColumnForByteOffset(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		columns  []int // Indexed by byte offset, starting at offset -1.
+	}{
+		{"", []int{0, 0, 0}},
+		{"a\u3042b", []int{0, 0, 1, 1, 1, 3, 4, 4}},
+		{"\u4e16\u754c", []int{0, 0, 0, 0, 2, 2, 2, 4, 4}},
+		{"e\u0301x", []int{0, 0, 0, 0, 1, 2}},         // Combining mark.
+		{"\u200ba\tb", []int{0, 0, 0, 0, 0, 1, 1, 2}}, // Clusters without width.
+	} {
+		for i, expected := range testCase.columns {
+			offset := i - 1
+			if column := ColumnForByteOffset(testCase.original, offset); column != expected {
+				t.Errorf("ColumnForByteOffset(%q, %d) is %d, expected %d", testCase.original, offset, column, expected)
+			}
+		}
+	}
+}
+
+// Test that ColumnForByteOffset and ByteOffsetForColumn round-trip.
+func TestColumnByteOffsetRoundTrip(t *testing.T) {
+	for _, original := range []string{
+		"Hello, \u4e16\u754c!",
+		"a\u3042b\u0301\U0001F468\u200d\U0001F469c",
+		"\uff21\uff22 x \U0001F1E9\U0001F1EA",
+	} {
+		var offset int
+		for str, state := original, -1; len(str) > 0; {
+			var (
+				cluster string
+				width   int
+			)
+			cluster, str, width, state = FirstGraphemeClusterInString(str, state)
+			column := ColumnForByteOffset(original, offset)
+			if width > 0 {
+				if o := ByteOffsetForColumn(original, column); o != offset {
+					t.Errorf("%q: offset %d maps to column %d which maps back to offset %d", original, offset, column, o)
+				}
+				if o := ByteOffsetForColumn(original, column+width-1); o != offset {
+					t.Errorf("%q: column %d maps to offset %d, expected %d", original, column+width-1, o, offset)
+				}
+			}
+			for i := 1; i < len(cluster); i++ {
+				if c := ColumnForByteOffset(original, offset+i); c != column {
+					t.Errorf("%q: offset %d maps to column %d, expected %d", original, offset+i, c, column)
+				}
+			}
+			offset += len(cluster)
+		}
+		if c := ColumnForByteOffset(original, len(original)); c != StringWidth(original) {
+			t.Errorf("%q: end maps to column %d, expected %d", original, c, StringWidth(original))
+		}
+	}
+}
+
+// Test the width of the widest line of a string.
+func TestMaxLineWidth(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"abc\n", 3},
+		{"a\nabcd\nab", 4},
+		{"ab\r\nabc\rabcde", 5},
+		{"\u4e16\u754c\nabc", 4},               // CJK.
+		{"abc\n\u4e16\u754c\u4f60\u597d\n", 8}, // CJK.
+		{"abc  \nab", 5},                       // Trailing spaces count.
+		{"a long line which wraps\nb", 23},     // Only mandatory breaks split lines.
+		{"ab\u2028abc\u0085a", 3},              // Line separator and NEL.
+		{"\U0001F468\u200d\U0001F469\nx", 2},   // Emoji.
+		{"\n\n\n", 0},
+	} {
+		if width := MaxLineWidth(testCase.original); width != testCase.expected {
+			t.Errorf("MaxLineWidth(%q) is %d, expected %d", testCase.original, width, testCase.expected)
+		}
+	}
+}
+
+// Test whether strings fit into a given width.
+func TestFits(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		width    int
+		expected bool
+	}{
+		{"", 0, true},
+		{"abc", 3, true},
+		{"abc", 2, false},
+		{"abc\n", 3, true},
+		{"\u4e16\u754c", 4, true},
+		{"\u4e16\u754c", 3, false},      // The second ideograph overflows by one column.
+		{"a\u4e16\u754c", 4, false},     // So does this one.
+		{"\u4e16\u754c\nabcd", 4, true}, // Mandatory break.
+		{"ab\nabcde\nabc", 4, false},    // Only the second line overflows.
+		{"a long line which wraps", 10, false},
+		{"abc  \nab", 4, false}, // Trailing spaces count.
+		{"e\u0301\u0301", 1, true},
+		{"\U0001F468\u200d\U0001F469", 2, true},
+		{"\U0001F468\u200d\U0001F469", 1, false},
+	} {
+		if fits := Fits(testCase.original, testCase.width); fits != testCase.expected {
+			t.Errorf("Fits(%q, %d) is %t, expected %t", testCase.original, testCase.width, fits, testCase.expected)
+		}
+		if fits := MaxLineWidth(testCase.original) <= testCase.width; fits != testCase.expected {
+			t.Errorf("%q: Fits doesn't agree with MaxLineWidth for width %d", testCase.original, testCase.width)
+		}
+	}
+}
+
+// Test that variation selectors after CJK ideographs, including the
+// ideographic variation selectors U+E0100 to U+E01EF, are Extend characters
+// which neither start a new grapheme cluster nor add to the width.
+func TestWidthIdeographicVariationSelectors(t *testing.T) {
+	for r := rune(0xe0100); r <= 0xe01ef; r++ {
+		if prop := propertyGraphemes(r); prop != prExtend {
+			t.Errorf("Grapheme property of %U is %d, expected Extend", r, prop)
+		}
+	}
+	for _, testCase := range []struct {
+		original string
+		clusters []string
+		widths   []int
+	}{
+		{"\u845b\U000E0100", []string{"\u845b\U000E0100"}, []int{2}},
+		{"\u845b\U000E0101x", []string{"\u845b\U000E0101", "x"}, []int{2, 1}},
+		{"\u8fbb\U000E0100\u8fbb", []string{"\u8fbb\U000E0100", "\u8fbb"}, []int{2, 2}},
+		{"\U00020000\U000E0100", []string{"\U00020000\U000E0100"}, []int{2}}, // Supplementary ideograph.
+		{"\u4e00\ufe00", []string{"\u4e00\ufe00"}, []int{2}},                 // Standardized variation sequence.
+		{"\u4e00\ufe0e", []string{"\u4e00\ufe0e"}, []int{2}},                 // VS15 doesn't narrow ideographs.
+		{"\u4e00\ufe0f", []string{"\u4e00\ufe0f"}, []int{2}},
+		{"\u845b\U000E0100\u0301", []string{"\u845b\U000E0100\u0301"}, []int{2}},
+		{"a\U000E0100", []string{"a\U000E0100"}, []int{1}},
+		{"\U000E0100", []string{"\U000E0100"}, []int{0}}, // Without a base.
+	} {
+		checkClusterWidths(t, testCase.original, testCase.clusters, testCase.widths)
+	}
+}
+
+// Test that C0 and C1 control characters, including NUL, have a width of 0
+// and form grapheme clusters of their own (GB4, GB5), with the exception of
+// CR LF (GB3).
+func TestWidthControlCharacters(t *testing.T) {
+	for r := rune(0); r <= 0x9f; r++ {
+		if r == 0x20 {
+			r = 0x7f // Skip printable ASCII.
+		}
+		for _, str := range []string{string(r) + "a", "a" + string(r), string(r) + "\u0301"} {
+			var (
+				clusters []string
+				widths   []int
+			)
+			rest, state := str, -1
+			for len(rest) > 0 {
+				var (
+					cluster    string
+					boundaries int
+				)
+				cluster, rest, boundaries, state = StepString(rest, state)
+				clusters = append(clusters, cluster)
+				widths = append(widths, boundaries>>ShiftWidth)
+			}
+			index := 0
+			if str[0] == 'a' {
+				index = 1
+			}
+			if len(clusters) != 2 || clusters[index] != string(r) {
+				t.Errorf("%q: got clusters %q, expected the control character on its own", str, clusters)
+			} else if widths[index] != 0 {
+				t.Errorf("%q: control character has width %d, expected 0", str, widths[index])
+			}
+		}
+	}
+	for _, testCase := range []struct {
+		original string
+		clusters int
+		width    int
+	}{
+		{"\x00", 1, 0},
+		{"\x00\x00\x00", 3, 0},
+		{"a\x00b", 3, 2},
+		{"\x00\u4e16\x00", 3, 2},
+		{"\x1b[31mred\x1b[0m", 12, 10}, // Escape sequences are not interpreted.
+		{"a\x7fb", 3, 2},
+		{"a\u0085b", 3, 2},
+		{"a\r\nb", 3, 2},
+		{"a\n\rb", 4, 2},
+		{"\x00\u0301", 2, 0},               // GB4: The combining mark has no base.
+		{"\x01\U0001F600\x02", 3, 2},       // Emoji between controls.
+		{"a\x00\u200db", 4, 2},             // ZWJ doesn't join across a control.
+		{"\U0001F1E9\x00\U0001F1EA", 3, 4}, // Neither do regional indicators.
+	} {
+		if count := GraphemeClusterCount(testCase.original); count != testCase.clusters {
+			t.Errorf("%q: got %d grapheme clusters, expected %d", testCase.original, count, testCase.clusters)
+		}
+		if width := StringWidth(testCase.original); width != testCase.width {
+			t.Errorf("%q: StringWidth is %d, expected %d", testCase.original, width, testCase.width)
+		}
+	}
+}
+
+// Benchmark the StringWidth function for different scripts.
+func BenchmarkStringWidth(b *testing.B) {
+	for _, corpus := range benchmarkCorpora {
+		b.Run(corpus.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(corpus.text)))
+			for i := 0; i < b.N; i++ {
+				resultCount = StringWidth(corpus.text)
+			}
+		})
+	}
+}
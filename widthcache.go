@@ -0,0 +1,91 @@
+package runeseg
+
+import (
+	"container/list"
+	"sync"
+)
+
+// WidthCache memoizes the monospace widths of strings as calculated by
+// [StringWidth]. This is useful for user interfaces which measure the same
+// labels over and over again, e.g. every time the screen is redrawn.
+//
+// The cache holds a limited number of strings. When it is full, the string
+// which was least recently measured is removed. Changing
+// [EastAsianAmbiguousWidth] clears the cache.
+//
+// A WidthCache may be used by multiple goroutines simultaneously. It must be
+// created with [NewWidthCache].
+type WidthCache struct {
+	// Protects all fields below.
+	mutex sync.Mutex
+
+	// The maximum number of strings held in the cache.
+	capacity int
+
+	// The value of EastAsianAmbiguousWidth the cached widths were calculated
+	// with.
+	ambiguousWidth int
+
+	// The cached entries, with the most recently used entry at the front.
+	order *list.List
+
+	// Maps strings to their elements in "order".
+	entries map[string]*list.Element
+}
+
+// widthCacheEntry is an entry of a [WidthCache].
+type widthCacheEntry struct {
+	str   string
+	width int
+}
+
+// NewWidthCache returns a new, empty cache which holds the widths of up to
+// "capacity" strings. A capacity of less than 1 is treated as 1.
+func NewWidthCache(capacity int) *WidthCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &WidthCache{
+		capacity:       capacity,
+		ambiguousWidth: EastAsianAmbiguousWidth,
+		order:          list.New(),
+		entries:        make(map[string]*list.Element),
+	}
+}
+
+// Width returns the monospace width of the given string, as [StringWidth]
+// does. The width is calculated only if it is not found in the cache.
+func (c *WidthCache) Width(s string) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// Invalidate if the width calculation has changed.
+	if c.ambiguousWidth != EastAsianAmbiguousWidth {
+		c.order.Init()
+		c.entries = make(map[string]*list.Element)
+		c.ambiguousWidth = EastAsianAmbiguousWidth
+	}
+
+	// Use the cached width if we have one.
+	if element, ok := c.entries[s]; ok {
+		c.order.MoveToFront(element)
+		return element.Value.(*widthCacheEntry).width
+	}
+
+	// Calculate and store it.
+	width := StringWidth(s)
+	if c.order.Len() >= c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*widthCacheEntry).str)
+	}
+	c.entries[s] = c.order.PushFront(&widthCacheEntry{str: s, width: width})
+	return width
+}
+
+// Len returns the number of strings currently held in the cache.
+func (c *WidthCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
@@ -0,0 +1,87 @@
+package runeseg
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Test that the cache returns the same widths as StringWidth.
+func TestWidthCache(t *testing.T) {
+	cache := NewWidthCache(2)
+	for _, s := range []string{"", "abc", "\u4e16\u754c", "abc", "\U0001F3F3\ufe0f\u200d\U0001F308", "\u4e16\u754c"} {
+		if w, expected := cache.Width(s), StringWidth(s); w != expected {
+			t.Errorf("%q: cached width %d, expected %d", s, w, expected)
+		}
+	}
+	if n := cache.Len(); n != 2 {
+		t.Errorf("Expected the cache to hold 2 strings, got %d", n)
+	}
+}
+
+// Test the eviction of the least recently used string.
+func TestWidthCacheEviction(t *testing.T) {
+	cache := NewWidthCache(2)
+	cache.Width("a")
+	cache.Width("b")
+	cache.Width("a") // "b" is now the least recently used string.
+	cache.Width("c")
+	cache.mutex.Lock()
+	_, hasA := cache.entries["a"]
+	_, hasB := cache.entries["b"]
+	_, hasC := cache.entries["c"]
+	cache.mutex.Unlock()
+	if !hasA || hasB || !hasC {
+		t.Errorf("Unexpected cache contents: a=%t b=%t c=%t", hasA, hasB, hasC)
+	}
+}
+
+// Test that changing the ambiguous width invalidates the cache.
+func TestWidthCacheAmbiguousWidth(t *testing.T) {
+	defer func(width int) { EastAsianAmbiguousWidth = width }(EastAsianAmbiguousWidth)
+	EastAsianAmbiguousWidth = 1
+	cache := NewWidthCache(10)
+	if w := cache.Width("±"); w != 1 {
+		t.Errorf("Expected width 1, got %d", w)
+	}
+	EastAsianAmbiguousWidth = 2
+	if w := cache.Width("±"); w != 2 {
+		t.Errorf("Expected width 2, got %d", w)
+	}
+}
+
+// Test concurrent use of the cache (run with -race).
+func TestWidthCacheConcurrent(t *testing.T) {
+	cache := NewWidthCache(8)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				s := fmt.Sprintf("label %d 世", (i+j)%16)
+				if w, expected := cache.Width(s), StringWidth(s); w != expected {
+					t.Errorf("%q: cached width %d, expected %d", s, w, expected)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// Benchmark measuring a string which is in the cache.
+func BenchmarkWidthCacheHit(b *testing.B) {
+	cache := NewWidthCache(16)
+	cache.Width(benchmarkStr)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resultCount = cache.Width(benchmarkStr)
+	}
+}
+
+// Benchmark measuring a string without a cache, for comparison.
+func BenchmarkWidthCacheMiss(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		resultCount = StringWidth(benchmarkStr)
+	}
+}
@@ -87,3 +87,117 @@ func FirstWordInString(str string, state int) (word, rest string, newState int)
 		}
 	}
 }
+
+// AppendWordBoundaries appends the byte offsets of all word boundaries in
+// the given string to "dst" and returns the extended slice, i.e. the offsets
+// at which the words returned by [FirstWordInString] end. The last offset is
+// always the length of the string. The start of the string is not included,
+// and nothing is appended for an empty string. Passing a slice with enough
+// capacity, e.g. dst[:0] from a previous call, avoids allocations.
+func AppendWordBoundaries(dst []int, s string) []int {
+	var (
+		word   string
+		offset int
+	)
+	state := -1
+	for len(s) > 0 {
+		word, s, state = FirstWordInString(s, state)
+		offset += len(word)
+		dst = append(dst, offset)
+	}
+	return dst
+}
+
+// WordsFiltered returns the word-like segments of the given string for which
+// the function "keep" returns true, e.g. to tokenize text for a search index
+// while removing stopwords. A segment returned by [FirstWordInString] is
+// word-like if it contains at least one letter or number. Other segments such
+// as spaces, punctuation, and emoji are dropped without calling "keep". The
+// words passed to "keep" and returned are substrings of "s", so dropping a
+// word does not allocate.
+func WordsFiltered(s string, keep func(word string) bool) []string {
+	var (
+		words []string
+		word  string
+	)
+	state := -1
+	for len(s) > 0 {
+		word, s, state = FirstWordInString(s, state)
+		if isWordLike(word) && keep(word) {
+			words = append(words, word)
+		}
+	}
+	return words
+}
+
+// isWordLike returns true if the given word segment contains at least one
+// letter or number.
+func isWordLike(word string) bool {
+	for _, r := range word {
+		switch _, gc := propertyLineBreak(r); gc {
+		case gcLu, gcLl, gcLC, gcLm, gcLo, gcNd, gcNl, gcNo:
+			return true
+		}
+	}
+	return false
+}
+
+// WordBoundary determines whether there is a word boundary before the rune
+// "r", given the state returned by the previous call. It lets you detect words
+// in a stream of runes without encoding them in UTF-8 first.
+//
+// Some rules need to look at the runes following "r". These are retrieved by
+// calling "lookahead" repeatedly, which must return the runes after "r" one at
+// a time, and false once the end of the text is reached. The function is only
+// called when needed and never more often than necessary. A nil function
+// indicates that "r" is the last rune of the text.
+//
+// For the first rune of the text, pass a state of -1. A boundary is always
+// reported before the first rune. For consecutive runes, pass the state
+// returned by the previous call. The end of the text is always a boundary.
+//
+// The state used by this function is not compatible with the states used by
+// [Step] or [FirstWord] and vice versa.
+func WordBoundary(state int, r rune, lookahead func() (rune, bool)) (boundary bool, newState int) {
+	if state < 0 {
+		state = -1
+	}
+
+	// WB6, WB7b, and WB12 look at the first rune after "r" which is not
+	// Extend, Format, or ZWJ.
+	var next []byte
+	switch property(wordBreakCodePoints, r) {
+	case prMidLetter, prMidNumLet, prSingleQuote, prDoubleQuote, prMidNum:
+		if s := state &^ wbZWJBit; state >= 0 && (s == wbALetter || s == wbHebrewLetter || s == wbNumeric) {
+			next = lookaheadRunes(lookahead, func(r rune) bool {
+				prop := property(wordBreakCodePoints, r)
+				return prop != prExtend && prop != prFormat && prop != prZWJ
+			})
+		}
+	}
+
+	newState, boundary = transitionWordBreakState(state, r, nil, string(next))
+	return boundary || state < 0, newState
+}
+
+// lookaheadRunes calls the "lookahead" function (which may be nil) until it
+// returns a rune for which "stop" returns true, until it returns false, or
+// until [MaxLookahead] runes were retrieved. It returns all runes retrieved
+// this way, encoded in UTF-8.
+func lookaheadRunes(lookahead func() (rune, bool), stop func(r rune) bool) []byte {
+	var b []byte
+	if lookahead == nil {
+		return b
+	}
+	for i := 0; i < MaxLookahead; i++ {
+		r, ok := lookahead()
+		if !ok {
+			return b
+		}
+		b = utf8.AppendRune(b, r)
+		if stop(r) {
+			return b
+		}
+	}
+	return b
+}
@@ -1,6 +1,9 @@
 package runeseg
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 // Test all official Unicode test cases for word boundaries using the byte slice
 // function.
@@ -144,6 +147,269 @@ func TestWordCasesString(t *testing.T) {
 	}
 }
 
+// runeLookahead returns a lookahead function for the rune-level boundary
+// primitives which returns the runes of the given slice one at a time.
+func runeLookahead(runes []rune) func() (rune, bool) {
+	return func() (rune, bool) {
+		if len(runes) == 0 {
+			return 0, false
+		}
+		r := runes[0]
+		runes = runes[1:]
+		return r, true
+	}
+}
+
+// Test that WordBoundary reproduces the output of FirstWord for the official
+// Unicode test cases.
+func TestWordBoundary(t *testing.T) {
+	for testNum, testCase := range wordBreakTestCases {
+		var expected []string
+		b, state := []byte(testCase.original), -1
+		for len(b) > 0 {
+			var word []byte
+			word, b, state = FirstWord(b, state)
+			expected = append(expected, string(word))
+		}
+
+		var (
+			words    []string
+			boundary bool
+		)
+		runes := []rune(testCase.original)
+		state = -1
+		for i, r := range runes {
+			boundary, state = WordBoundary(state, r, runeLookahead(runes[i+1:]))
+			if boundary {
+				words = append(words, "")
+			}
+			words[len(words)-1] += string(r)
+		}
+
+		if len(words) != len(expected) {
+			t.Errorf("Test case %d %q: got words %q, FirstWord returned %q", testNum, testCase.original, words, expected)
+			continue
+		}
+		for i := range words {
+			if words[i] != expected[i] {
+				t.Errorf("Test case %d %q: word %d is %q, FirstWord returned %q", testNum, testCase.original, i, words[i], expected[i])
+			}
+		}
+	}
+}
+
+// Test that numbers with any kind of digit grouping are single words (WB11,
+// WB12), both with FirstWordInString and with StepString.
+func TestWordNumericGrouping(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"1,000,000", []string{"1,000,000"}},       // Western grouping.
+		{"1,00,000", []string{"1,00,000"}},         // Indian grouping.
+		{"12,34,56,789", []string{"12,34,56,789"}}, // Indian grouping, crore.
+		{"\u0967,\u0966\u0966,\u0966\u0966\u0966", []string{"\u0967,\u0966\u0966,\u0966\u0966\u0966"}}, // Devanagari digits.
+		{"3.14", []string{"3.14"}},
+		{"1,234.56", []string{"1,234.56"}},
+		{"1.234,56", []string{"1.234,56"}},
+		{"pay 1,00,000 now", []string{"pay", " ", "1,00,000", " ", "now"}},
+		{"1,00,", []string{"1,00", ","}}, // A trailing separator is not part of the number.
+		{"1,,0", []string{"1", ",", ",", "0"}},
+	} {
+		var words []string
+		str, state := testCase.original, -1
+		for len(str) > 0 {
+			var word string
+			word, str, state = FirstWordInString(str, state)
+			words = append(words, word)
+		}
+		var (
+			stepWords []string
+			current   string
+		)
+		str, state = testCase.original, -1
+		for len(str) > 0 {
+			var (
+				cluster    string
+				boundaries int
+			)
+			cluster, str, boundaries, state = StepString(str, state)
+			current += cluster
+			if boundaries&MaskWord != 0 {
+				stepWords = append(stepWords, current)
+				current = ""
+			}
+		}
+		for _, result := range [][]string{words, stepWords} {
+			if strings.Join(result, "|") != strings.Join(testCase.expected, "|") {
+				t.Errorf("%q: got words %q, expected %q", testCase.original, result, testCase.expected)
+			}
+		}
+	}
+}
+
+// Test that the katakana middle dot U+30FB, which has no word break property,
+// separates words.
+func TestWordKatakanaMiddleDot(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"\u6771\u4eac\u30fb\u5927\u962a", []string{"\u6771", "\u4eac", "\u30fb", "\u5927", "\u962a"}}, // Ideographs are single words (WB999).
+		{"\u30c8\u30e0\u30fb\u30af\u30eb\u30fc\u30ba", []string{"\u30c8\u30e0", "\u30fb", "\u30af\u30eb\u30fc\u30ba"}},
+		{"a\u30fbb", []string{"a", "\u30fb", "b"}},
+	} {
+		var words []string
+		str, state := testCase.original, -1
+		for len(str) > 0 {
+			var word string
+			word, str, state = FirstWordInString(str, state)
+			words = append(words, word)
+		}
+		if strings.Join(words, "|") != strings.Join(testCase.expected, "|") {
+			t.Errorf("%q: got words %q, expected %q", testCase.original, words, testCase.expected)
+		}
+	}
+}
+
+// Test that CR LF is a single word segment (WB3) with boundaries before and
+// after it (WB3a, WB3b), with FirstWordInString, StepString, and
+// WordBoundary.
+func TestWordCRLF(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"a\r\nb", []string{"a", "\r\n", "b"}},
+		{"\r\n", []string{"\r\n"}},
+		{"a\r\n\r\nb", []string{"a", "\r\n", "\r\n", "b"}},
+		{"a \r\n b", []string{"a", " ", "\r\n", " ", "b"}},
+		{"a\r\rb", []string{"a", "\r", "\r", "b"}},
+		{"a\n\rb", []string{"a", "\n", "\r", "b"}},
+		{"a\r\n\u0308", []string{"a", "\r\n", "\u0308"}}, // WB4 does not apply after newlines.
+	} {
+		var words []string
+		str, state := testCase.original, -1
+		for len(str) > 0 {
+			var word string
+			word, str, state = FirstWordInString(str, state)
+			words = append(words, word)
+		}
+		var (
+			stepWords []string
+			current   string
+		)
+		str, state = testCase.original, -1
+		for len(str) > 0 {
+			var (
+				cluster    string
+				boundaries int
+			)
+			cluster, str, boundaries, state = StepString(str, state)
+			current += cluster
+			if boundaries&MaskWord != 0 {
+				stepWords = append(stepWords, current)
+				current = ""
+			}
+		}
+		var runeWords []string
+		runes := []rune(testCase.original)
+		state = -1
+		for i, r := range runes {
+			var boundary bool
+			boundary, state = WordBoundary(state, r, runeLookahead(runes[i+1:]))
+			if boundary {
+				runeWords = append(runeWords, "")
+			}
+			runeWords[len(runeWords)-1] += string(r)
+		}
+		for _, result := range [][]string{words, stepWords, runeWords} {
+			if strings.Join(result, "|") != strings.Join(testCase.expected, "|") {
+				t.Errorf("%q: got words %q, expected %q", testCase.original, result, testCase.expected)
+			}
+		}
+	}
+}
+
+// Test the WordsFiltered function.
+func TestWordsFiltered(t *testing.T) {
+	stopwords := map[string]bool{"the": true, "a": true, "an": true}
+	keep := func(word string) bool {
+		return !stopwords[strings.ToLower(word)]
+	}
+	for _, testCase := range []struct {
+		original string
+		expected []string
+	}{
+		{"", nil},
+		{"The cat ate an apple and a pear.", []string{"cat", "ate", "apple", "and", "pear"}},
+		{"\"A\" is the 1st letter, isn't it?", []string{"is", "1st", "letter", "isn't", "it"}},
+		{"\u6771\u4eac\u30fb\u5927\u962a", []string{"\u6771", "\u4eac", "\u5927", "\u962a"}},
+		{"see 3.14 \U0001F600 \u00bd!", []string{"see", "3.14", "\u00bd"}},
+		{"the a an", nil},
+	} {
+		words := WordsFiltered(testCase.original, keep)
+		if strings.Join(words, "|") != strings.Join(testCase.expected, "|") || len(words) != len(testCase.expected) {
+			t.Errorf("%q: got words %q, expected %q", testCase.original, words, testCase.expected)
+		}
+	}
+
+	// Only word-like segments are passed to "keep".
+	WordsFiltered("Hello, world! \U0001F600", func(word string) bool {
+		if word != "Hello" && word != "world" {
+			t.Errorf("Segment %q was passed to keep", word)
+		}
+		return true
+	})
+}
+
+// segmentEnds returns the byte offsets at which the given segments end.
+func segmentEnds(segments [][]rune) (offsets []int) {
+	var offset int
+	for _, segment := range segments {
+		offset += len(string(segment))
+		offsets = append(offsets, offset)
+	}
+	return
+}
+
+// equalOffsets returns whether the two slices of offsets are equal.
+func equalOffsets(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Test the AppendWordBoundaries function.
+func TestAppendWordBoundaries(t *testing.T) {
+	for testNum, testCase := range wordBreakTestCases {
+		expected := segmentEnds(testCase.expected)
+		if offsets := AppendWordBoundaries(nil, testCase.original); !equalOffsets(offsets, expected) {
+			t.Errorf("Test case %d %q: got offsets %v, expected %v", testNum, testCase.original, offsets, expected)
+		}
+	}
+
+	// Existing elements are kept and the buffer is reused.
+	dst := make([]int, 1, 16)
+	dst[0] = -1
+	offsets := AppendWordBoundaries(dst, "Hello, world!")
+	if !equalOffsets(offsets, []int{-1, 5, 6, 7, 12, 13}) {
+		t.Errorf("Got offsets %v", offsets)
+	}
+	if &offsets[0] != &dst[0] {
+		t.Error("Buffer with enough capacity was not reused")
+	}
+	if offsets := AppendWordBoundaries(dst[:0], ""); len(offsets) != 0 {
+		t.Errorf("Got offsets %v for empty string", offsets)
+	}
+}
+
 // Benchmark the use of the word break function for byte slices.
 func BenchmarkWordFunctionBytes(b *testing.B) {
 	for i := 0; i < b.N; i++ {
@@ -169,3 +435,75 @@ func BenchmarkWordFunctionString(b *testing.B) {
 		}
 	}
 }
+
+// Benchmark AppendWordBoundaries with a new and a reused slice.
+func BenchmarkAppendWordBoundaries(b *testing.B) {
+	b.Run("New", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			resultOffsets = AppendWordBoundaries(nil, benchmarkStr)
+		}
+	})
+	b.Run("Reuse", func(b *testing.B) {
+		b.ReportAllocs()
+		var offsets []int
+		for i := 0; i < b.N; i++ {
+			offsets = AppendWordBoundaries(offsets[:0], benchmarkStr)
+		}
+		resultOffsets = offsets
+	})
+}
+
+// Test that fullwidth letters and digits are ALetter and Numeric, so
+// fullwidth text is segmented like its halfwidth equivalent.
+func TestWordFullwidth(t *testing.T) {
+	for r := rune(0xff21); r <= 0xff3a; r++ { // Ａ to Ｚ.
+		if p := property(wordBreakCodePoints, r); p != prALetter {
+			t.Errorf("%U: got property %d, expected ALetter", r, p)
+		}
+		if p := property(wordBreakCodePoints, r+0x20); p != prALetter { // ａ to ｚ.
+			t.Errorf("%U: got property %d, expected ALetter", r+0x20, p)
+		}
+	}
+	for r := rune(0xff10); r <= 0xff19; r++ { // ０ to ９.
+		if p := property(wordBreakCodePoints, r); p != prNumeric {
+			t.Errorf("%U: got property %d, expected Numeric", r, p)
+		}
+	}
+
+	// toHalfwidth maps fullwidth ASCII variants and the ideographic space to
+	// their halfwidth equivalents.
+	toHalfwidth := func(r rune) rune {
+		switch {
+		case r >= 0xff01 && r <= 0xff5e:
+			return r - 0xfee0
+		case r == 0x3000:
+			return ' '
+		}
+		return r
+	}
+	for _, original := range []string{
+		"\uff21\uff22\uff23\uff11\uff12\uff13",
+		"\uff48\uff45\uff4c\uff4c\uff4f\uff0c\u3000\uff57\uff4f\uff52\uff4c\uff44\uff01",
+		"\uff13\uff0e\uff11\uff14",
+		"\uff49\uff54\uff07\uff53",
+		"\uff41\uff3f\uff42",
+		"\uff12\uff10\uff12\uff16\uff0d\uff11\uff10\u3000\uff56\uff12\uff0e\uff10\uff0e\uff11",
+	} {
+		halfwidth := strings.Map(toHalfwidth, original)
+		var fullWords, halfWords []string
+		for str, state := original, -1; len(str) > 0; {
+			var word string
+			word, str, state = FirstWordInString(str, state)
+			fullWords = append(fullWords, strings.Map(toHalfwidth, word))
+		}
+		for str, state := halfwidth, -1; len(str) > 0; {
+			var word string
+			word, str, state = FirstWordInString(str, state)
+			halfWords = append(halfWords, word)
+		}
+		if strings.Join(fullWords, "|") != strings.Join(halfWords, "|") {
+			t.Errorf("%q: got words %q, expected %q as for %q", original, fullWords, halfWords, halfwidth)
+		}
+	}
+}
@@ -1894,3 +1894,11 @@ var wordBreakCodePoints = [][3]int{
 	{0xE0020, 0xE007F, prExtend},               // Cf  [96] TAG SPACE..CANCEL TAG
 	{0xE0100, 0xE01EF, prExtend},               // Mn [240] VARIATION SELECTOR-17..VARIATION SELECTOR-256
 }
+
+// wordBreakCodePointsVersion is the Unicode version of the data file
+// wordBreakCodePoints was generated from.
+// wordBreakCodePointsDate is the date of the generation.
+const (
+	wordBreakCodePointsVersion = "17.0.0"
+	wordBreakCodePointsDate    = "2026-01-05"
+)
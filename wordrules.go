@@ -232,14 +232,15 @@ func transitionWordBreakState(state int, r rune, b []byte, str string) (newState
 	// For those rules that need to look up runes further in the string, we
 	// determine the property after nextProperty, skipping over Format, Extend,
 	// and ZWJ (according to WB4). It's -1 if not needed, if such a rune cannot
-	// be determined (because the text ends or the rune is faulty).
+	// be determined (because the text ends, the rune is faulty, or it is
+	// further away than MaxLookahead runes).
 	farProperty := -1
 	if rule > 60 &&
 		(state == wbALetter || state == wbHebrewLetter || state == wbNumeric) &&
 		(nextProperty == prMidLetter || nextProperty == prMidNumLet || nextProperty == prSingleQuote || // WB6.
 			nextProperty == prDoubleQuote || // WB7b.
 			nextProperty == prMidNum) { // WB12.
-		for {
+		for i := 0; i < MaxLookahead; i++ {
 			var (
 				r      rune
 				length int
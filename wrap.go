@@ -0,0 +1,371 @@
+package runeseg
+
+import "unicode/utf8"
+
+// WrapMode determines where a [WordWrapper] may break lines.
+type WrapMode int
+
+// The wrap modes.
+const (
+	// WrapDefault breaks lines at all line break opportunities determined by
+	// the rules of Unicode Standard Annex #14, e.g. after hyphens and between
+	// ideographs.
+	WrapDefault WrapMode = iota
+
+	// WrapWordOnly breaks lines only after white space (see
+	// [IsWhitespaceCluster]) and at mandatory line breaks, provided these are
+	// line break opportunities. Words are never split: A word which doesn't
+	// fit into an empty line is placed on its own line, exceeding the width,
+	// even if [WrapOptions.BreakLongTokens] is set. Text without spaces, such
+	// as Chinese or Japanese, is therefore not wrapped at all.
+	WrapWordOnly
+)
+
+// WrapOptions specifies how text is wrapped by a [WordWrapper] or
+// [WrapString]. The zero value wraps text without any indentation.
+type WrapOptions struct {
+	// Mode determines where lines may be broken.
+	Mode WrapMode
+
+	// Indent is prepended to every line.
+	Indent string
+
+	// HangingIndent is prepended, after Indent, to every line except the
+	// first line of each paragraph, i.e. except the first line of the text and
+	// lines following a mandatory line break. This is typically used to align
+	// the continuation lines of a list item with the item's text, e.g. "  "
+	// for items starting with "- ".
+	HangingIndent string
+
+	// BreakLongTokens causes segments which don't fit into an empty line, such
+	// as long URLs, to be broken at the last grapheme cluster boundary at
+	// which the line still fits. Without this option, such segments are placed
+	// on their own line, exceeding the width. Segments which don't fit at the
+	// end of a non-empty line are still moved to the next line first.
+	BreakLongTokens bool
+
+	// WidthOptions configures how the widths of the text and the indentation
+	// are measured, e.g. the width of East Asian Ambiguous characters. The
+	// text is measured like [Options.StringWidth] does with these options.
+	// To obtain widths consistent with the wrapped lines, measure them with
+	// the same options, e.g. via [Options.StringWidth], instead of relying on
+	// [EastAsianAmbiguousWidth].
+	WidthOptions Options
+}
+
+// WordWrapper implements an iterator over the lines of a string wrapped to a
+// given monospace width. Lines are broken at the line break opportunities
+// determined by the rules of [Unicode Standard Annex #14], choosing the last
+// opportunity at which the line still fits into the width ("greedy" wrapping).
+// Lines are also broken after mandatory line breaks such as newline
+// characters. [WrapOptions.Mode] restricts the opportunities which are used.
+//
+// After constructing the class via [NewWordWrapper], [WordWrapper.Next] is
+// called in a loop until it returns false. Inside the loop, the current line is
+// available via [WordWrapper.Line]. Whitespace and line break characters at the
+// end of a line are not part of the line. A trailing newline at the end of the
+// text does not result in an additional empty line.
+//
+// The width available to the text of a line is reduced by the width of its
+// indentation (see [WrapOptions]). Each line contains at least one segment
+// between two line break opportunities, even if it does not fit into the
+// width, unless [WrapOptions.BreakLongTokens] is set, in which case it
+// contains at least one grapheme cluster. This is also the case if the
+// indentation alone exceeds the width.
+//
+// Soft hyphens (U+00AD) allow a line break after them and have a width of 0.
+// A line broken at a soft hyphen ends with it. Since the wrapper does not
+// insert hyphens, callers which want a visible hyphen at such breaks need to
+// replace the trailing soft hyphen, e.g. with "-", which may then exceed the
+// width by one column.
+//
+// [Unicode Standard Annex #14]: https://www.unicode.org/reports/tr14/
+type WordWrapper struct {
+	// The original string.
+	original string
+
+	// The maximum width of a line, including its indentation.
+	width int
+
+	// The wrapping options.
+	options WrapOptions
+
+	// The byte offset of the remaining string to be wrapped.
+	offset int
+
+	// The current state of the [StepWithOptions] parser.
+	state int
+
+	// The byte offsets of the current line's text in the original string.
+	start, end int
+
+	// Whether the current line continues a paragraph, i.e. whether it
+	// follows a line which was wrapped.
+	continued bool
+
+	// Whether the next line continues a paragraph.
+	continues bool
+}
+
+// NewWordWrapper returns a new iterator over the lines of the string "str"
+// wrapped to the given monospace width.
+func NewWordWrapper(str string, width int, options WrapOptions) *WordWrapper {
+	return &WordWrapper{
+		original: str,
+		width:    width,
+		options:  options,
+		state:    -1,
+	}
+}
+
+// Next advances the iterator by one line and returns false if no lines are
+// left. This function must be called before the first line is accessed.
+func (w *WordWrapper) Next() bool {
+	w.start, w.end = w.offset, w.offset
+	if w.offset >= len(w.original) {
+		return false
+	}
+	w.continued, w.continues = w.continues, true
+
+	// Determine the width available to the text.
+	indentWidth := w.options.WidthOptions.StringWidth(w.Indent())
+	budget := w.width - indentWidth
+
+	// Add segments to the line as long as they fit.
+	var (
+		lineWidth int  // The width of the line, including trailing whitespace.
+		placed    bool // Whether at least one segment was added to the line.
+	)
+	for w.offset < len(w.original) {
+		// Determine the next segment.
+		segmentStart, segmentState := w.offset, w.state
+		var (
+			contentEnd              = segmentStart // The end of the segment without trailing whitespace.
+			contentWidth, fullWidth int
+			mustBreak               bool
+			str                     = w.original[w.offset:]
+			cluster                 string
+			boundaries              int
+		)
+		for len(str) > 0 {
+			clusterStart, clusterState := w.offset, w.state
+			cluster, str, boundaries, w.state = StepStringWithOptions(str, w.state, w.options.WidthOptions)
+			w.offset += len(cluster)
+			fullWidth += w.options.WidthOptions.columnWidth(cluster, boundaries>>ShiftWidth, indentWidth+lineWidth+fullWidth)
+			if !isLineEndCluster(cluster) {
+				if w.options.BreakLongTokens && w.options.Mode != WrapWordOnly && !placed && contentEnd > segmentStart && fullWidth > budget {
+					// Break the segment before this cluster.
+					w.offset, w.state = clusterStart, clusterState
+					w.end = contentEnd
+					return true
+				}
+				contentEnd, contentWidth = w.offset, fullWidth
+			}
+			if w.isBreak(cluster, boundaries) {
+				mustBreak = boundaries&MaskLine == LineMustBreak
+				break
+			}
+		}
+
+		// Wrap if it doesn't fit.
+		if placed && contentEnd > segmentStart && lineWidth+contentWidth > budget {
+			w.offset, w.state = segmentStart, segmentState
+			break
+		}
+
+		// Add it to the line.
+		placed = true
+		if contentEnd > segmentStart {
+			w.end = contentEnd
+		}
+		lineWidth += fullWidth
+		if mustBreak {
+			w.continues = false
+			break
+		}
+	}
+
+	return true
+}
+
+// isBreak returns whether the line may be broken after the given grapheme
+// cluster with the given boundary information, according to the wrap mode.
+func (w *WordWrapper) isBreak(cluster string, boundaries int) bool {
+	switch boundaries & MaskLine {
+	case LineMustBreak:
+		return true
+	case LineCanBreak:
+		return w.options.Mode != WrapWordOnly || IsWhitespaceCluster(cluster)
+	}
+	return false
+}
+
+// Text returns the text of the current line, without its indentation, as a
+// substring of the original string. If the iterator is already past the end
+// or [WordWrapper.Next] has not yet been called, an empty string is returned.
+func (w *WordWrapper) Text() string {
+	return w.original[w.start:w.end]
+}
+
+// Indent returns the indentation of the current line, as specified in the
+// [WrapOptions].
+func (w *WordWrapper) Indent() string {
+	if w.continued {
+		return w.options.Indent + w.options.HangingIndent
+	}
+	return w.options.Indent
+}
+
+// Line returns the current line, including its indentation.
+func (w *WordWrapper) Line() string {
+	return w.Indent() + w.Text()
+}
+
+// WrapString wraps the given string to the given monospace width and returns
+// the resulting lines, including their indentation. See [WordWrapper] for
+// details.
+func WrapString(str string, width int, options WrapOptions) []string {
+	var lines []string
+	w := NewWordWrapper(str, width, options)
+	for w.Next() {
+		lines = append(lines, w.Line())
+	}
+	return lines
+}
+
+// WrappedLineCount returns the number of lines [WrapString] would return for
+// the same arguments, without building the lines. This is useful to determine
+// the height of wrapped text, e.g. for scrolling.
+func WrappedLineCount(str string, width int, options WrapOptions) (n int) {
+	w := NewWordWrapper(str, width, options)
+	for w.Next() {
+		n++
+	}
+	return
+}
+
+// SplitWidth splits the given string into consecutive pieces with a monospace
+// width of at most "width" each, e.g. to fill the cells of a grid. Unlike
+// [WrapString], it has no notion of lines: All characters, including spaces
+// and line break characters, are kept in the pieces and count towards their
+// widths. Concatenating the pieces results in the original string.
+//
+// Grapheme clusters are never split. A piece ends at the last line break
+// opportunity (see [Step]) at which it still fits into the width. If there is
+// no such opportunity, it is filled with as many grapheme clusters as fit,
+// i.e. a wide character which doesn't fit into the remaining width is moved
+// to the next piece. A single grapheme cluster wider than the width is
+// returned as its own piece.
+func SplitWidth(s string, width int) (pieces []string) {
+	var (
+		start, pos           int // The current piece is s[start:pos].
+		pieceWidth           int // The width of the current piece.
+		breakPos, breakWidth int // The last break opportunity in the current piece and the piece's width up to it.
+	)
+	str, state := s, -1
+	for len(str) > 0 {
+		var (
+			cluster    string
+			boundaries int
+		)
+		cluster, str, boundaries, state = StepString(str, state)
+		clusterWidth := boundaries >> ShiftWidth
+
+		// Start new pieces until the cluster fits.
+		for pos > start && pieceWidth+clusterWidth > width {
+			cut := pos
+			if breakPos > start {
+				cut = breakPos
+				pieceWidth -= breakWidth
+			} else {
+				pieceWidth = 0
+			}
+			pieces = append(pieces, s[start:cut])
+			start = cut
+		}
+
+		pos += len(cluster)
+		pieceWidth += clusterWidth
+		if boundaries&MaskLine != LineDontBreak {
+			breakPos, breakWidth = pos, pieceWidth
+		}
+	}
+	if pos > start {
+		pieces = append(pieces, s[start:pos])
+	}
+	return
+}
+
+// NextLineBreakWithin determines where the first line of the given text ends
+// when it is wrapped greedily to a monospace width of "maxWidth", with widths
+// measured as [Options.StringWidth] does with the given options. It returns
+// the byte offset at which the first line ends and the text of the next line
+// begins. This is the basic step of a greedy line wrapper such as
+// [WordWrapper], exposed for custom wrapping implementations. The text is
+// treated as the start of a text, so to find the following lines, call it
+// again with the text after "breakAt".
+//
+// As in [WordWrapper], whitespace at the end of a line does not count towards
+// its width. The result is one of the following:
+//
+//   - If a mandatory line break is reached before the width is exceeded,
+//     "breakAt" is the offset after the line break character(s) and
+//     "mustBreak" is true. The end of the text is also a mandatory break
+//     (LB3), i.e. if the entire text fits, len(s) and true are returned.
+//   - Otherwise, "breakAt" is the last line break opportunity at which the
+//     line still fits into the width.
+//   - If there is no such opportunity, "breakAt" is the last grapheme
+//     cluster boundary at which the line still fits and "hardBreak" is true.
+//     The first grapheme cluster is always included, even if it does not fit,
+//     so that "breakAt" is greater than 0.
+//
+// For an empty string, 0 is returned.
+func NextLineBreakWithin(s string, maxWidth int, opts Options) (breakAt int, mustBreak, hardBreak bool) {
+	var (
+		pos                 int // The end of the text which fits.
+		width, contentWidth int // The width of the scanned text, with and without trailing whitespace.
+		cluster             string
+		boundaries          int
+	)
+	str, state := s, -1
+	for len(str) > 0 {
+		cluster, str, boundaries, state = StepStringWithOptions(str, state, opts)
+		width += opts.columnWidth(cluster, boundaries>>ShiftWidth, width)
+		if !isLineEndCluster(cluster) {
+			contentWidth = width
+		}
+		if contentWidth > maxWidth {
+			break
+		}
+		pos += len(cluster)
+		switch boundaries & MaskLine {
+		case LineMustBreak:
+			return pos, true, false
+		case LineCanBreak:
+			breakAt = pos
+		}
+	}
+	if breakAt > 0 {
+		return breakAt, false, false
+	}
+	if pos == 0 {
+		pos = len(cluster)
+	}
+	return pos, false, pos > 0
+}
+
+// isLineEndCluster returns true if the given grapheme cluster consists only of
+// spaces and mandatory line break characters, i.e. characters which are not
+// visible at the end of a line.
+func isLineEndCluster(cluster string) bool {
+	for len(cluster) > 0 {
+		r, length := utf8.DecodeRuneInString(cluster)
+		switch prop, _ := propertyLineBreak(r); prop {
+		case prSP, prBK, prCR, prLF, prNL:
+		default:
+			return false
+		}
+		cluster = cluster[length:]
+	}
+	return true
+}
@@ -0,0 +1,302 @@
+package runeseg
+
+import (
+	"strings"
+	"testing"
+)
+
+// Test the basic wrapping of text.
+func TestWrapString(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		width    int
+		expected []string
+	}{
+		{"", 10, nil},
+		{"Hello", 10, []string{"Hello"}},
+		{"Hello, world!", 10, []string{"Hello,", "world!"}},
+		{"Hello, world!", 13, []string{"Hello, world!"}},
+		{"The quick brown fox jumps over the lazy dog.", 15, []string{"The quick brown", "fox jumps over", "the lazy dog."}},
+		{"a     b", 3, []string{"a", "b"}}, // Trailing spaces don't count.
+		{"Supercalifragilistic is long", 10, []string{"Supercalifragilistic", "is long"}},
+		{"First line.\nSecond line.", 20, []string{"First line.", "Second line."}},
+		{"a\n\nb\n", 20, []string{"a", "", "b"}},
+		{"a\r\nb", 20, []string{"a", "b"}},
+		{"  indented", 20, []string{"  indented"}},
+		{"well-known fact", 6, []string{"well-", "known", "fact"}},
+		{"co\u00adoperate now", 4, []string{"co\u00ad", "operate", "now"}},      // Soft hyphen.
+		{"hy\u00adphen\u00adation", 6, []string{"hy\u00adphen\u00ad", "ation"}}, // Soft hyphens have no width.
+		{"世界你好世界", 5, []string{"世界", "你好", "世界"}},
+		{"\U0001F469\u200d\U0001F467 \U0001F469\u200d\U0001F467", 3, []string{"\U0001F469\u200d\U0001F467", "\U0001F469\u200d\U0001F467"}},
+	} {
+		lines := WrapString(testCase.original, testCase.width, WrapOptions{})
+		if strings.Join(lines, "|") != strings.Join(testCase.expected, "|") || len(lines) != len(testCase.expected) {
+			t.Errorf("%q wrapped at %d yields %q, expected %q", testCase.original, testCase.width, lines, testCase.expected)
+		}
+	}
+}
+
+// Test indentation and hanging indentation.
+func TestWrapStringIndent(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		width    int
+		options  WrapOptions
+		expected []string
+	}{
+		{
+			"one two three four", 10, WrapOptions{Indent: "> "},
+			[]string{"> one two", "> three", "> four"},
+		},
+		{
+			"- The first item of the list", 12, WrapOptions{HangingIndent: "  "},
+			[]string{"- The first", "  item of", "  the list"},
+		},
+		{
+			"- Wrapped item in an indented list", 16, WrapOptions{Indent: "    ", HangingIndent: "  "},
+			[]string{"    - Wrapped", "      item in an", "      indented", "      list"},
+		},
+		{
+			"- First item\n- Second item", 8, WrapOptions{HangingIndent: "  "},
+			[]string{"- First", "  item", "- Second", "  item"},
+		},
+		{
+			"1. 世界你好", 7, WrapOptions{HangingIndent: "   "},
+			[]string{"1. 世界", "   你好"},
+		},
+		{
+			"a b c", 3, WrapOptions{Indent: "\u3000\u3000"}, // Indent exceeds the width.
+			[]string{"\u3000\u3000a", "\u3000\u3000b", "\u3000\u3000c"},
+		},
+	} {
+		lines := WrapString(testCase.original, testCase.width, testCase.options)
+		if strings.Join(lines, "|") != strings.Join(testCase.expected, "|") || len(lines) != len(testCase.expected) {
+			t.Errorf("%q wrapped at %d with %+v yields %q, expected %q", testCase.original, testCase.width, testCase.options, lines, testCase.expected)
+			continue
+		}
+		for _, line := range lines {
+			if w := StringWidth(line); w > testCase.width && StringWidth(testCase.options.Indent) < testCase.width {
+				t.Errorf("%q wrapped at %d: line %q has width %d", testCase.original, testCase.width, line, w)
+			}
+		}
+	}
+}
+
+// Test breaking segments which don't fit into a line.
+func TestWrapStringBreakLongTokens(t *testing.T) {
+	url := "https://example.com/" + strings.Repeat("abcdefghij", 18)
+	var expected []string
+	for i := 0; i < len(url); i += 20 {
+		expected = append(expected, url[i:i+20])
+	}
+	for _, testCase := range []struct {
+		original string
+		width    int
+		options  WrapOptions
+		expected []string
+	}{
+		{url, 20, WrapOptions{BreakLongTokens: true}, expected},
+		{url, 20, WrapOptions{}, []string{url[:20], url[20:]}}, // Break after "/" (LB31).
+		{"see " + url[20:50] + " now", 20, WrapOptions{BreakLongTokens: true}, []string{"see", url[20:40], url[40:50] + " now"}},
+		{"Supercalifragilistic is long", 10, WrapOptions{BreakLongTokens: true}, []string{"Supercalif", "ragilistic", "is long"}},
+		{"\u4e16\u754c\u4f60\u597dabcdefgh", 5, WrapOptions{BreakLongTokens: true}, []string{"\u4e16\u754c", "\u4f60\u597d", "abcde", "fgh"}},
+		{"xa\u0301b\u0301c\u0301d", 2, WrapOptions{BreakLongTokens: true}, []string{"xa\u0301", "b\u0301c\u0301", "d"}}, // Clusters are not broken.
+		{"abcdef", 5, WrapOptions{Indent: "> ", BreakLongTokens: true}, []string{"> abc", "> def"}},
+		{"abc", 1, WrapOptions{Indent: "> ", BreakLongTokens: true}, []string{"> a", "> b", "> c"}}, // Indent exceeds the width.
+	} {
+		lines := WrapString(testCase.original, testCase.width, testCase.options)
+		if strings.Join(lines, "|") != strings.Join(testCase.expected, "|") || len(lines) != len(testCase.expected) {
+			t.Errorf("%q wrapped at %d with %+v yields %q, expected %q", testCase.original, testCase.width, testCase.options, lines, testCase.expected)
+		}
+	}
+}
+
+// Test wrapping only at white space.
+func TestWrapStringWordOnly(t *testing.T) {
+	url := "https://example.com/" + strings.Repeat("abcdefghij", 3)
+	for _, testCase := range []struct {
+		original string
+		width    int
+		options  WrapOptions
+		expected []string
+	}{
+		{"the quick brown fox", 10, WrapOptions{Mode: WrapWordOnly}, []string{"the quick", "brown fox"}},
+		{"see " + url + " now", 20, WrapOptions{Mode: WrapWordOnly}, []string{"see", url, "now"}},
+		{"see " + url + " now", 20, WrapOptions{Mode: WrapWordOnly, BreakLongTokens: true}, []string{"see", url, "now"}},
+		{"see " + url + " now", 20, WrapOptions{}, []string{"see https://", "example.com/", url[20:], "now"}},
+		{"a well-known fact", 8, WrapOptions{Mode: WrapWordOnly}, []string{"a", "well-known", "fact"}},
+		{"a well-known fact", 8, WrapOptions{}, []string{"a well-", "known", "fact"}},
+		{"Supercalifragilistic is long", 10, WrapOptions{Mode: WrapWordOnly}, []string{"Supercalifragilistic", "is long"}},
+		{"\u4e16\u754c\u4f60\u597d \u4e16\u754c", 4, WrapOptions{Mode: WrapWordOnly}, []string{"\u4e16\u754c\u4f60\u597d", "\u4e16\u754c"}},
+		{"one\ntwo three", 20, WrapOptions{Mode: WrapWordOnly}, []string{"one", "two three"}},
+		{"a\u00a0b c", 3, WrapOptions{Mode: WrapWordOnly}, []string{"a\u00a0b", "c"}}, // No break after a no-break space.
+		{"word-only mode", 8, WrapOptions{Mode: WrapWordOnly, Indent: "> "}, []string{"> word-only", "> mode"}},
+	} {
+		lines := WrapString(testCase.original, testCase.width, testCase.options)
+		if strings.Join(lines, "|") != strings.Join(testCase.expected, "|") || len(lines) != len(testCase.expected) {
+			t.Errorf("%q wrapped at %d with %+v yields %q, expected %q", testCase.original, testCase.width, testCase.options, lines, testCase.expected)
+		}
+		if n := WrappedLineCount(testCase.original, testCase.width, testCase.options); n != len(lines) {
+			t.Errorf("%q: WrappedLineCount returned %d, expected %d", testCase.original, n, len(lines))
+		}
+	}
+}
+
+// Test the NextLineBreakWithin function.
+func TestNextLineBreakWithin(t *testing.T) {
+	for _, testCase := range []struct {
+		original             string
+		maxWidth             int
+		opts                 Options
+		breakAt              int
+		mustBreak, hardBreak bool
+	}{
+		{"", 5, Options{}, 0, false, false},
+		{"foo bar baz", 7, Options{}, 8, false, false},
+		{"foo bar baz", 6, Options{}, 4, false, false},
+		{"foo    bar", 4, Options{}, 7, false, false}, // Trailing spaces don't count.
+		{"foo bar", 10, Options{}, 7, true, false},    // End of text.
+		{"foo\nbar baz", 10, Options{}, 4, true, false},
+		{"foo\r\nbar", 3, Options{}, 5, true, false},
+		{"well-known fact", 7, Options{}, 5, false, false},
+		{"\u4e16\u754c\u4f60\u597d", 5, Options{}, 6, false, false},
+		{"foobarbaz qux", 4, Options{}, 4, false, true},
+		{"xa\u0301b\u0301c", 2, Options{}, 4, false, true}, // Clusters are not broken.
+		{"\u4e16\u754c", 1, Options{}, 3, false, true},     // The first cluster is always included.
+		{"a b", 0, Options{}, 1, false, true},
+		{"a\tb c", 6, Options{}, 5, true, false},
+		{"a\tb c", 6, Options{TabWidth: 4}, 4, false, false},
+		{"abc\tx y", 5, Options{TabStop: 4}, 6, false, false},
+		{"abc\tx y", 5, Options{TabWidth: 4}, 3, false, true},
+		{"\u03b1\u03b2 \u03b3", 3, Options{}, 5, false, false},
+		{"\u03b1\u03b2 \u03b3", 3, Options{AmbiguousWidth: 2}, 2, false, true},
+	} {
+		breakAt, mustBreak, hardBreak := NextLineBreakWithin(testCase.original, testCase.maxWidth, testCase.opts)
+		if breakAt != testCase.breakAt || mustBreak != testCase.mustBreak || hardBreak != testCase.hardBreak {
+			t.Errorf("%q at %d with %+v: got %d, %t, %t, expected %d, %t, %t", testCase.original, testCase.maxWidth, testCase.opts, breakAt, mustBreak, hardBreak, testCase.breakAt, testCase.mustBreak, testCase.hardBreak)
+		}
+	}
+}
+
+// Test that the width options drive the measurement of wrapped lines.
+func TestWrapStringWidthOptions(t *testing.T) {
+	text := "\u03b1\u03b1 \u03b1\u03b1 \u03b1\u03b1 \u03b1\u03b1" // Ambiguous characters.
+	for _, testCase := range []struct {
+		original string
+		options  WrapOptions
+		expected []string
+	}{
+		{text, WrapOptions{}, []string{"\u03b1\u03b1 \u03b1\u03b1 \u03b1\u03b1", "\u03b1\u03b1"}},
+		{text, WrapOptions{WidthOptions: Options{AmbiguousWidth: 1}}, []string{"\u03b1\u03b1 \u03b1\u03b1 \u03b1\u03b1", "\u03b1\u03b1"}},
+		{text, WrapOptions{WidthOptions: Options{AmbiguousWidth: 2}}, []string{"\u03b1\u03b1", "\u03b1\u03b1", "\u03b1\u03b1", "\u03b1\u03b1"}},
+		{text, WrapOptions{Indent: "\u03b1", WidthOptions: Options{AmbiguousWidth: 2}}, []string{"\u03b1\u03b1\u03b1", "\u03b1\u03b1\u03b1", "\u03b1\u03b1\u03b1", "\u03b1\u03b1\u03b1"}},
+		{"a\tb\tc d", WrapOptions{}, []string{"a\tb\tc d"}},
+		{"a\tb\tc d", WrapOptions{WidthOptions: Options{TabWidth: 4}}, []string{"a\t", "b\tc d"}},
+		{"a\tb\tc d", WrapOptions{WidthOptions: Options{TabStop: 4}}, []string{"a\tb\t", "c d"}},
+		{"a\tb\tc d", WrapOptions{Indent: "\t", WidthOptions: Options{TabStop: 4}}, []string{"\ta\t", "\tb\t", "\tc d"}},
+		{"ab\tc\nabcdefg\tx", WrapOptions{WidthOptions: Options{TabStop: 4}}, []string{"ab\tc", "abcdefg\t", "x"}},
+	} {
+		lines := WrapString(testCase.original, 8, testCase.options)
+		if strings.Join(lines, "|") != strings.Join(testCase.expected, "|") || len(lines) != len(testCase.expected) {
+			t.Errorf("%q with %+v yields %q, expected %q", testCase.original, testCase.options, lines, testCase.expected)
+		}
+		if n := WrappedLineCount(testCase.original, 8, testCase.options); n != len(testCase.expected) {
+			t.Errorf("%q with %+v: WrappedLineCount returned %d, expected %d", testCase.original, testCase.options, n, len(testCase.expected))
+		}
+		for _, line := range lines {
+			if width := testCase.options.WidthOptions.StringWidth(line); width > 8 {
+				t.Errorf("%q with %+v: line %q has width %d", testCase.original, testCase.options, line, width)
+			}
+		}
+	}
+}
+
+// Test that WrappedLineCount agrees with WrapString.
+func TestWrappedLineCount(t *testing.T) {
+	text := "The quick brown fox jumps over the lazy dog.\n\nA second paragraph, with a\nmandatory break and a reallyreallylongtoken.\n"
+	for _, testCase := range []struct {
+		width    int
+		options  WrapOptions
+		expected int
+	}{
+		{100, WrapOptions{}, 4},
+		{40, WrapOptions{}, 6},
+		{20, WrapOptions{}, 9},
+		{10, WrapOptions{}, 13},
+		{10, WrapOptions{BreakLongTokens: true}, 15},
+		{20, WrapOptions{Indent: "    "}, 10},
+	} {
+		n := WrappedLineCount(text, testCase.width, testCase.options)
+		if lines := WrapString(text, testCase.width, testCase.options); n != len(lines) {
+			t.Errorf("Width %d with %+v: WrappedLineCount returned %d, WrapString %d lines", testCase.width, testCase.options, n, len(lines))
+		}
+		if n != testCase.expected {
+			t.Errorf("Width %d with %+v: got %d lines, expected %d", testCase.width, testCase.options, n, testCase.expected)
+		}
+	}
+	if n := WrappedLineCount("", 10, WrapOptions{}); n != 0 {
+		t.Errorf("Expected 0 lines for an empty string, got %d", n)
+	}
+}
+
+// Test the SplitWidth function.
+func TestSplitWidth(t *testing.T) {
+	for _, testCase := range []struct {
+		original string
+		width    int
+		expected []string
+	}{
+		{"", 3, nil},
+		{"abcdefgh", 3, []string{"abc", "def", "gh"}},
+		{"ab cd ef", 3, []string{"ab ", "cd ", "ef"}},
+		{"a bcdef", 5, []string{"a ", "bcdef"}},                               // Break at the opportunity.
+		{"line one\nline two", 6, []string{"line ", "one\n", "line ", "two"}}, // Newlines are kept.
+		{"a\u4e16", 2, []string{"a", "\u4e16"}},                               // Wide character doesn't fit into the remainder.
+		{"abc\u4e16\u754c", 4, []string{"abc", "\u4e16\u754c"}},
+		{"\u4e16\u754cab", 3, []string{"\u4e16", "\u754c", "ab"}},
+		{"\u4e16\u754c", 1, []string{"\u4e16", "\u754c"}}, // Wider than the width.
+		{"he\u0301llo", 3, []string{"he\u0301l", "lo"}},   // Clusters are not split.
+		{"\U0001F1E9\U0001F1EA\U0001F1EB\U0001F1F7", 3, []string{"\U0001F1E9\U0001F1EA", "\U0001F1EB\U0001F1F7"}},
+		{"abc", 0, []string{"a", "b", "c"}},
+	} {
+		pieces := SplitWidth(testCase.original, testCase.width)
+		if strings.Join(pieces, "|") != strings.Join(testCase.expected, "|") || len(pieces) != len(testCase.expected) {
+			t.Errorf("SplitWidth(%q, %d) yields %q, expected %q", testCase.original, testCase.width, pieces, testCase.expected)
+		}
+		if joined := strings.Join(pieces, ""); joined != testCase.original {
+			t.Errorf("SplitWidth(%q, %d): pieces join to %q", testCase.original, testCase.width, joined)
+		}
+	}
+}
+
+// Test the WordWrapper iterator.
+func TestWordWrapper(t *testing.T) {
+	w := NewWordWrapper("- Bullet points are\n- nice", 13, WrapOptions{Indent: " ", HangingIndent: "  "})
+	var indents, texts []string
+	for w.Next() {
+		indents = append(indents, w.Indent())
+		texts = append(texts, w.Text())
+	}
+	if strings.Join(texts, "|") != "- Bullet|points are|- nice" {
+		t.Errorf("Unexpected texts %q", texts)
+	}
+	if strings.Join(indents, "|") != " |   | " {
+		t.Errorf("Unexpected indents %q", indents)
+	}
+	if w.Next() || w.Text() != "" {
+		t.Error("Iterator not exhausted")
+	}
+}
+
+// Benchmark the WrapString function for different scripts.
+func BenchmarkWrapString(b *testing.B) {
+	for _, corpus := range benchmarkCorpora {
+		b.Run(corpus.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(corpus.text)))
+			for i := 0; i < b.N; i++ {
+				resultCount = len(WrapString(corpus.text, 40, WrapOptions{}))
+			}
+		})
+	}
+}